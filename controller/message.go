@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type IMessageController interface {
+	GetMessages(c *gin.Context)
+}
+
+type messageController struct {
+	logger         *utils.Logger
+	messageService service.IMessageService
+}
+
+func NewMessageController(logger *utils.Logger, messageSvc service.IMessageService) *messageController {
+	return &messageController{
+		logger:         logger,
+		messageService: messageSvc,
+	}
+}
+
+// GetMessages pages backwards through the chat history of a presentation.
+// Pass the `next_before` of a page as `before` to get the page before it
+func (m *messageController) GetMessages(c *gin.Context) {
+	presId := c.Param("id")
+	before := utils.Str2Uint(c.DefaultQuery("before", "0"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultMessagePageSize)))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "invalid limit"})
+		return
+	}
+
+	messages, err := m.messageService.GetMessages(presId, before, limit)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "messages not found!"})
+		m.logger.Error(err.Error())
+		return
+	}
+
+	var nextBefore uint
+	if len(messages) > 0 {
+		nextBefore = messages[0].Id
+	}
+
+	c.JSON(http.StatusOK, map[string]any{
+		"messages":    messages,
+		"next_before": nextBefore,
+	})
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"advanced-webapp-project/websocket"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IQuestionController interface {
+	GetQuestions(c *gin.Context)
+	AskQuestion(c *gin.Context)
+	UpvoteQuestion(c *gin.Context)
+	MarkAnswered(c *gin.Context)
+}
+
+type questionController struct {
+	logger             *utils.Logger
+	jwtService         service.IJWTService
+	questionService    service.IQuestionService
+	presService        service.IPresService
+	userService        service.IUserService
+	participantService service.IParticipantService
+	publisher          websocket.Publisher
+}
+
+func NewQuestionController(logger *utils.Logger, jwtSvc service.IJWTService, questionSvc service.IQuestionService, presSvc service.IPresService, userSvc service.IUserService, participantSvc service.IParticipantService, publisher websocket.Publisher) *questionController {
+	return &questionController{
+		logger:             logger,
+		jwtService:         jwtSvc,
+		questionService:    questionSvc,
+		presService:        presSvc,
+		userService:        userSvc,
+		participantService: participantSvc,
+		publisher:          publisher,
+	}
+}
+
+func (q *questionController) GetQuestions(c *gin.Context) {
+	presId := c.Param("id")
+	sortBy := c.DefaultQuery("sort", service.QuestionSortVotes)
+
+	questions, err := q.questionService.GetQuestions(presId, sortBy)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "questions not found!"})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]any{
+		"questions": questions,
+	})
+}
+
+func (q *questionController) AskQuestion(c *gin.Context) {
+	presId := c.Param("id")
+	userId := q.getUserId(c.GetHeader("Authorization"))
+
+	var question model.Question
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	question.PresentationId = utils.Str2Uint(presId)
+	question.UserId = utils.Str2Uint(userId)
+	question.ParticipantId = q.participantService.ForUser(userId)
+	if user, err := q.userService.GetProfile(userId); err == nil {
+		question.Username = user.Username
+	}
+
+	err := q.questionService.AskQuestion(&question)
+	switch {
+	case errors.Is(err, service.ErrInvalidQuestion):
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to ask question"})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	q.publisher.Publish(presId, websocket.TypeQuestion, question)
+	c.JSON(http.StatusCreated, map[string]any{
+		"data": question,
+	})
+}
+
+func (q *questionController) UpvoteQuestion(c *gin.Context) {
+	presId := c.Param("id")
+	questionId := c.Param("question_id")
+	userId := q.getUserId(c.GetHeader("Authorization"))
+
+	question, err := q.questionService.UpvoteQuestion(presId, questionId, userId, q.participantService.ForUser(userId))
+	switch {
+	case errors.Is(err, service.ErrQuestionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "question not found"})
+		return
+	case errors.Is(err, service.ErrAlreadyUpvoted):
+		c.AbortWithStatusJSON(http.StatusConflict, map[string]any{"message": "already upvoted"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to upvote question"})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	q.publisher.Publish(presId, websocket.TypeQuestion, question)
+	c.JSON(http.StatusOK, map[string]any{
+		"data": question,
+	})
+}
+
+// MarkAnswered marks or unmarks a question as answered. Only the owner of the
+// presentation may do it
+func (q *questionController) MarkAnswered(c *gin.Context) {
+	presId := c.Param("id")
+	questionId := c.Param("question_id")
+	userId := q.getUserId(c.GetHeader("Authorization"))
+
+	pres, err := q.presService.GetPresentationById(presId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "presentation not found"})
+		q.logger.Error(err.Error())
+		return
+	}
+	if utils.Uint2Str(pres.Owner.Id) != userId {
+		c.AbortWithStatusJSON(http.StatusForbidden, map[string]any{"message": "only the presenter can mark questions as answered"})
+		return
+	}
+
+	var body struct {
+		IsAnswered bool `json:"is_answered"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	question, err := q.questionService.MarkAnswered(presId, questionId, body.IsAnswered)
+	switch {
+	case errors.Is(err, service.ErrQuestionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "question not found"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to update question"})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	q.publisher.Publish(presId, websocket.TypeQuestion, question)
+	c.JSON(http.StatusOK, map[string]any{
+		"data": question,
+	})
+}
+
+func (q *questionController) getUserId(token string) string {
+	claims, _ := q.jwtService.ExtractToken(token)
+	return claims["user_id"].(string)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IQuizController interface {
+	GetLeaderboard(c *gin.Context)
+}
+
+type quizController struct {
+	logger         *utils.Logger
+	quizService    service.IQuizService
+	sessionService service.ISessionService
+}
+
+func NewQuizController(logger *utils.Logger, quizSvc service.IQuizService, sessionSvc service.ISessionService) *quizController {
+	return &quizController{
+		logger:         logger,
+		quizService:    quizSvc,
+		sessionService: sessionSvc,
+	}
+}
+
+// GetLeaderboard ranks the participants of the session given in the
+// `session_id` query, of the latest session by default
+func (q *quizController) GetLeaderboard(c *gin.Context) {
+	presId := c.Param("id")
+	sessionId, ok := resolveSession(c, q.sessionService, q.logger)
+	if !ok {
+		return
+	}
+
+	leaderboard, err := q.quizService.InSession(sessionId).GetLeaderboard(presId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "leaderboard not found!"})
+		q.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]any{
+		"leaderboard": leaderboard,
+	})
+}
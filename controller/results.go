@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+type IResultsController interface {
+	ExportResults(c *gin.Context)
+}
+
+type resultsController struct {
+	logger         *utils.Logger
+	resultsService service.IResultsService
+	sessionService service.ISessionService
+}
+
+func NewResultsController(logger *utils.Logger, resultsSvc service.IResultsService, sessionSvc service.ISessionService) *resultsController {
+	return &resultsController{
+		logger:         logger,
+		resultsService: resultsSvc,
+		sessionService: sessionSvc,
+	}
+}
+
+// ExportResults downloads the results of a presentation per slide and per
+// option, as CSV or as a spreadsheet with `?format=xlsx`. Results are those
+// of the session given in the `session_id` query, of the latest by default
+func (r *resultsController) ExportResults(c *gin.Context) {
+	presId := c.Param("id")
+
+	format := c.DefaultQuery("format", "csv")
+	write, contentType := service.WriteResultsCSV, "text/csv; charset=utf-8"
+	switch format {
+	case "csv":
+	case "xlsx":
+		write, contentType = service.WriteResultsXLSX, xlsxContentType
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "format must be csv or xlsx"})
+		return
+	}
+
+	sessionId, ok := resolveSession(c, r.sessionService, r.logger)
+	if !ok {
+		return
+	}
+
+	results, err := r.resultsService.GetResults(presId, sessionId)
+	switch {
+	case errors.Is(err, service.ErrPresNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "presentation not found"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to export results"})
+		r.logger.Error(err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = write(&buf, results); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to export results"})
+		r.logger.Error(err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="results-%s.%s"`, presId, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ISessionController interface {
+	GetSessions(c *gin.Context)
+	GetSession(c *gin.Context)
+}
+
+type sessionController struct {
+	logger         *utils.Logger
+	sessionService service.ISessionService
+	slideService   service.ISlideService
+}
+
+func NewSessionController(logger *utils.Logger, sessionSvc service.ISessionService, slideSvc service.ISlideService) *sessionController {
+	return &sessionController{
+		logger:         logger,
+		sessionService: sessionSvc,
+		slideService:   slideSvc,
+	}
+}
+
+// GetSessions lists the times a presentation was presented, latest first
+func (s *sessionController) GetSessions(c *gin.Context) {
+	presId := c.Param("id")
+
+	sessions, err := s.sessionService.GetSessions(presId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "sessions not found!"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]any{
+		"sessions": sessions,
+	})
+}
+
+// GetSession returns a session along with the slides of the presentation
+// holding the results of that session
+func (s *sessionController) GetSession(c *gin.Context) {
+	presId := c.Param("id")
+
+	session, err := s.sessionService.GetSession(presId, c.Param("session_id"))
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "session not found"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to get session"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	slides, err := s.slideService.InSession(session.Id).GetAllSlides(presId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "slides not found!"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]any{
+		"session": session,
+		"slides":  slides,
+	})
+}
+
+// resolveSession reads the session whose results are asked for from the
+// `session_id` query, the latest session of the presentation by default.
+// It responds on its own and reports false when the session is not found
+func resolveSession(c *gin.Context, sessionSvc service.ISessionService, logger *utils.Logger) (uint, bool) {
+	sessionId, err := sessionSvc.ResolveSession(c.Param("id"), c.Query("session_id"))
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "session not found"})
+		return 0, false
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to get session"})
+		logger.Error(err.Error())
+		return 0, false
+	}
+	return sessionId, true
+}
@@ -4,7 +4,11 @@ import (
 	"advanced-webapp-project/model"
 	"advanced-webapp-project/service"
 	"advanced-webapp-project/utils"
+	"advanced-webapp-project/websocket"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
 	"strconv"
 )
@@ -13,26 +17,48 @@ type ISlideController interface {
 	GetAllSlides(c *gin.Context)
 	CreateSlide(c *gin.Context)
 	UpdateSlide(c *gin.Context)
-	UpdateOptionVote(c *gin.Context)
+	SubmitVote(c *gin.Context)
+	GetHeatmap(c *gin.Context)
+	GetSlideTypes(c *gin.Context)
+	ReorderSlides(c *gin.Context)
+	DuplicateSlide(c *gin.Context)
+	CopyPresentation(c *gin.Context)
+	ExportPresentation(c *gin.Context)
+	ImportPresentation(c *gin.Context)
+	ImportMarkdown(c *gin.Context)
 	DeleteSlide(c *gin.Context)
 }
 
 type slideController struct {
-	logger       *utils.Logger
-	slideService service.ISlideService
+	logger             *utils.Logger
+	jwtService         service.IJWTService
+	slideService       service.ISlideService
+	sessionService     service.ISessionService
+	participantService service.IParticipantService
+	rooms              websocket.RoomUpdater
 }
 
-func NewSlideController(logger *utils.Logger, slideSvc service.ISlideService) *slideController {
+func NewSlideController(logger *utils.Logger, jwtSvc service.IJWTService, slideSvc service.ISlideService, sessionSvc service.ISessionService, participantSvc service.IParticipantService, rooms websocket.RoomUpdater) *slideController {
 	return &slideController{
-		logger:       logger,
-		slideService: slideSvc,
+		logger:             logger,
+		jwtService:         jwtSvc,
+		slideService:       slideSvc,
+		sessionService:     sessionSvc,
+		participantService: participantSvc,
+		rooms:              rooms,
 	}
 }
 
+// GetAllSlides lists the slides of a presentation with the results of the
+// session given in the `session_id` query, of the latest session by default
 func (s *slideController) GetAllSlides(c *gin.Context) {
 	presId := c.Param("id")
+	sessionId, ok := resolveSession(c, s.sessionService, s.logger)
+	if !ok {
+		return
+	}
 
-	slides, err := s.slideService.GetAllSlides(presId)
+	slides, err := s.slideService.InSession(sessionId).GetAllSlides(presId)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "slides not found!"})
 		s.logger.Error(err.Error())
@@ -44,10 +70,17 @@ func (s *slideController) GetAllSlides(c *gin.Context) {
 	})
 }
 
+// CreateSlide adds a slide to a presentation, at the `index` given in the
+// query or last
 func (s *slideController) CreateSlide(c *gin.Context) {
 	presId := c.Param("id")
 	slideId := utils.GenerateRandomNumber(8)
 	contentId := utils.GenerateRandomNumber(8)
+	index, err := strconv.Atoi(c.DefaultQuery("index", "-1"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "invalid index"})
+		return
+	}
 
 	var slide model.Slide
 	if err := c.ShouldBindJSON(&slide); err != nil {
@@ -56,42 +89,18 @@ func (s *slideController) CreateSlide(c *gin.Context) {
 		return
 	}
 
-	slide.PresentationId = utils.Str2Uint(presId)
-	slide.Id = utils.Str2Uint(slideId)
-
-	err := s.slideService.CreateSlide(&slide)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "failed to create slide"})
-		s.logger.Error(err.Error())
-		return
-	}
-
-	content := slide.Content
-	content.Id = utils.Str2Uint(contentId)
-	err = s.slideService.CreateContent(slideId, content)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "failed to create content"})
-		s.logger.Error(err.Error())
+	if err := s.slideService.ValidateSlide(&slide); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
 		return
 	}
 
-	switch slide.Type {
-	case 1:
-		options := content.Options
-		err = s.slideService.CreateOption(contentId, options)
-	case 8:
-		heading := content.Heading
-		err = s.slideService.CreateHeading(contentId, heading)
-	case 9:
-		paragraph := content.Paragraph
-		err = s.slideService.CreateParagraph(contentId, paragraph)
-	default:
-		s.logger.Warn("Create slide was interrupted")
-		return
-	}
+	slide.PresentationId = utils.Str2Uint(presId)
+	slide.Id = utils.Str2Uint(slideId)
+	slide.Content.Id = utils.Str2Uint(contentId)
 
+	err = s.slideService.CreateSlide(&slide, index)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "failed to create slide content"})
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "failed to create slide"})
 		s.logger.Error(err.Error())
 		return
 	}
@@ -113,64 +122,273 @@ func (s *slideController) UpdateSlide(c *gin.Context) {
 		return
 	}
 
+	if err := s.slideService.ValidateSlide(&slide); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	}
+
 	slideIdUint, _ := strconv.ParseUint(slideId, 10, 64)
 	slide.Id = uint(slideIdUint)
 
-	_, err := s.slideService.UpdateSlide(presId, slide)
+	err := s.slideService.UpdateSlide(presId, contentId, &slide)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to update slide"})
 		s.logger.Error(err.Error())
 		return
 	}
 
-	_, err = s.slideService.UpdateContent(slideId, *slide.Content)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to update content"})
+	c.JSON(http.StatusOK, map[string]any{
+		"message": "updated successfully",
+	})
+}
+
+// SubmitVote records a vote in the running session of the presentation
+func (s *slideController) SubmitVote(c *gin.Context) {
+	presId := c.Param("id")
+	contentId := c.Param("content_id")
+	optionId := c.Query("option_id")
+	participantId := s.participantService.ForUser(s.getUserId(c.GetHeader("Authorization")))
+
+	session, err := s.sessionService.GetCurrentSession(presId)
+	switch {
+	case errors.Is(err, service.ErrNoSession):
+		c.AbortWithStatusJSON(http.StatusConflict, map[string]any{"message": err.Error()})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to vote option"})
 		s.logger.Error(err.Error())
 		return
 	}
 
-	switch slide.Type {
-	case 1:
-		_, err = s.slideService.UpdateOptions(contentId, slide.Content.Options)
-	case 8:
-		_, err = s.slideService.UpdateHeading(contentId, slide.Content.Heading)
-	case 9:
-		_, err = s.slideService.UpdateParagraph(contentId, slide.Content.Paragraph)
-	default:
-		s.logger.Warn("Update slide was interrupted")
+	err = s.slideService.InSession(session.Id).SubmitVote(contentId, optionId, participantId)
+	switch {
+	case errors.Is(err, service.ErrOptionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "option not found"})
+		return
+	case errors.Is(err, service.ErrAlreadyVoted):
+		c.AbortWithStatusJSON(http.StatusConflict, map[string]any{"message": "already voted"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to vote option"})
+		s.logger.Error(err.Error())
 		return
 	}
 
+	c.JSON(http.StatusOK, map[string]any{
+		"message": "voted successfully",
+	})
+}
+
+// GetHeatmap bins the pins of a pin on image slide at the `resolution` given
+// in the query, 10 by 10 cells by default. Pins are those of the session
+// given in the `session_id` query, of the latest session by default
+func (s *slideController) GetHeatmap(c *gin.Context) {
+	presId := c.Param("id")
+	slideId := c.Param("slide_id")
+	resolution, err := strconv.Atoi(c.DefaultQuery("resolution", strconv.Itoa(service.DefaultHeatmapResolution)))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to update sub-content"})
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "invalid resolution"})
+		return
+	}
+	sessionId, ok := resolveSession(c, s.sessionService, s.logger)
+	if !ok {
+		return
+	}
+	slideService := s.slideService.InSession(sessionId)
+
+	slide, err := slideService.GetSlideById(slideId)
+	if err != nil || utils.Uint2Str(slide.PresentationId) != presId {
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "slide not found"})
+		return
+	}
+
+	heatmap, err := slideService.GetHeatmap(slide, resolution)
+	switch {
+	case errors.Is(err, service.ErrNotPinOnImage), errors.Is(err, service.ErrInvalidResolution):
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to get heatmap"})
 		s.logger.Error(err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, map[string]any{
-		"message": "updated successfully",
+		"heatmap": heatmap,
 	})
 }
 
-func (s *slideController) UpdateOptionVote(c *gin.Context) {
-	contentId := c.Param("content_id")
-	optionId := c.Query("option_id")
-
-	res, err := s.slideService.UpdateOptionVote(contentId, optionId)
+// GetSlideTypes lists the slide types by category, each with the JSON schema
+// of the content its slides expect
+func (s *slideController) GetSlideTypes(c *gin.Context) {
+	categories, err := s.slideService.GetSlideTypes()
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to vote option"})
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to get slide types"})
 		s.logger.Error(err.Error())
 		return
 	}
 
-	if res == 0 {
-		c.AbortWithStatusJSON(http.StatusConflict, map[string]any{"message": "failed to vote option"})
+	c.JSON(http.StatusOK, map[string]any{
+		"categories": categories,
+	})
+}
+
+// ReorderSlides moves slides of a presentation into the order given in the
+// body, which may leave out slides staying in place, and the live room along
+func (s *slideController) ReorderSlides(c *gin.Context) {
+	presId := c.Param("id")
+
+	var body struct {
+		SlideIds []uint `json:"slide_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
 		return
 	}
 
+	order, err := s.slideService.ReorderSlides(presId, body.SlideIds)
+	switch {
+	case errors.Is(err, service.ErrInvalidOrder):
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to reorder slides"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	s.rooms.ReorderSlides(presId, order)
+
 	c.JSON(http.StatusOK, map[string]any{
-		"message": "voted successfully",
+		"slide_ids": order,
+	})
+}
+
+func (s *slideController) DuplicateSlide(c *gin.Context) {
+	presId := c.Param("id")
+	slideId := c.Param("slide_id")
+
+	slide, err := s.slideService.DuplicateSlide(presId, slideId)
+	switch {
+	case errors.Is(err, service.ErrSlideNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "slide not found"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to duplicate slide"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]any{
+		"data": slide,
+	})
+}
+
+// CopyPresentation copies a presentation and its slides into a new
+// presentation owned by the caller
+func (s *slideController) CopyPresentation(c *gin.Context) {
+	presId := c.Param("id")
+	userId := s.getUserId(c.GetHeader("Authorization"))
+
+	pres, err := s.slideService.CopyPresentation(presId, userId)
+	switch {
+	case errors.Is(err, service.ErrPresNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "presentation not found"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to copy presentation"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]any{
+		"data": pres,
+	})
+}
+
+// ExportPresentation downloads a presentation as a JSON document which
+// ImportPresentation takes back
+func (s *slideController) ExportPresentation(c *gin.Context) {
+	presId := c.Param("id")
+
+	doc, err := s.slideService.ExportPresentation(presId)
+	switch {
+	case errors.Is(err, service.ErrPresNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, map[string]any{"message": "presentation not found"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to export presentation"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="presentation-%s.json"`, presId))
+	c.JSON(http.StatusOK, doc)
+}
+
+// ImportPresentation recreates the presentation of a document for the
+// caller, or reports every problem of the document
+func (s *slideController) ImportPresentation(c *gin.Context) {
+	userId := s.getUserId(c.GetHeader("Authorization"))
+
+	var doc model.PresDocument
+	if err := c.ShouldBindJSON(&doc); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	}
+
+	pres, err := s.slideService.ImportPresentation(&doc, userId)
+	var docErr *service.DocumentError
+	switch {
+	case errors.As(err, &docErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{
+			"message": "invalid document",
+			"errors":  docErr.Problems,
+		})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to import presentation"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]any{
+		"data": pres,
+	})
+}
+
+// ImportMarkdown recreates a presentation for the caller out of the Markdown
+// outline sent as the body, named after the `name` given in the query or
+// the first heading of the outline
+func (s *slideController) ImportMarkdown(c *gin.Context) {
+	userId := s.getUserId(c.GetHeader("Authorization"))
+
+	markdown, err := io.ReadAll(io.LimitReader(c.Request.Body, service.MaxMarkdownSize+1))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": "failed to read markdown"})
+		return
+	}
+
+	pres, err := s.slideService.ImportMarkdown(string(markdown), c.Query("name"), userId)
+	var docErr *service.DocumentError
+	switch {
+	case errors.Is(err, service.ErrInvalidMarkdown), errors.Is(err, service.ErrHeadingTooLong):
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+		return
+	case errors.As(err, &docErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, map[string]any{
+			"message": "invalid document",
+			"errors":  docErr.Problems,
+		})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"message": "failed to import presentation"})
+		s.logger.Error(err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, map[string]any{
+		"data": pres,
 	})
 }
 
@@ -194,3 +412,8 @@ func (s *slideController) DeleteSlide(c *gin.Context) {
 		"message": "deleted successfully",
 	})
 }
+
+func (s *slideController) getUserId(token string) string {
+	claims, _ := s.jwtService.ExtractToken(token)
+	return claims["user_id"].(string)
+}
@@ -33,25 +33,55 @@ var (
 	sqlDB        = db.NewSQLDB()
 	logger       = utils.NewLogger()
 
-	userRepo  = repository.NewUserRepo(sqlDB)
-	groupRepo = repository.NewGroupRepo(sqlDB)
-	presRepo  = repository.NewPresRepo(sqlDB)
-	slideRepo = repository.NewSlideRepo(sqlDB)
-
-	jwtService   = service.NewJWTService(logger)
-	mailService  = service.NewMailerService(logger)
-	authService  = service.NewAuthService(userRepo)
-	userService  = service.NewUserService(userRepo)
-	groupService = service.NewGroupService(groupRepo)
-	presService  = service.NewPresService(presRepo)
-	slideService = service.NewSlideService(slideRepo)
-
-	authController  = controller.NewAuthHandler(logger, jwtService, authService, mailService)
-	oauthController = controller.NewOauthController(logger, jwtService, authService)
-	userController  = controller.NewUserController(logger, jwtService, userService, groupService)
-	groupController = controller.NewGroupController(logger, jwtService, groupService, userService, authService, mailService)
-	presController  = controller.NewPresController(logger, jwtService, presService, userService)
-	slideController = controller.NewSlideController(logger, slideService)
+	userRepo     = repository.NewUserRepo(sqlDB)
+	groupRepo    = repository.NewGroupRepo(sqlDB)
+	presRepo     = repository.NewPresRepo(sqlDB)
+	slideRepo    = repository.NewSlideRepo(sqlDB)
+	questionRepo = repository.NewQuestionRepo(sqlDB)
+	messageRepo  = repository.NewMessageRepo(sqlDB)
+	quizRepo     = repository.NewQuizRepo(sqlDB)
+	sessionRepo  = repository.NewSessionRepo(sqlDB)
+	unitOfWork   = repository.NewUnitOfWork(sqlDB)
+
+	jwtService         = service.NewJWTService(logger)
+	mailService        = service.NewMailerService(logger)
+	authService        = service.NewAuthService(userRepo)
+	userService        = service.NewUserService(userRepo)
+	groupService       = service.NewGroupService(groupRepo)
+	presService        = service.NewPresService(presRepo)
+	slideService       = service.NewSlideService(slideRepo, unitOfWork)
+	participantService = service.NewParticipantService()
+	questionService    = service.NewQuestionService(questionRepo, unitOfWork)
+	messageService     = service.NewMessageService(messageRepo)
+	quizService        = service.NewQuizService(quizRepo)
+	sessionService     = service.NewSessionService(sessionRepo, unitOfWork)
+	resultsService     = service.NewResultsService(slideService, quizService, presService)
+
+	wsServices = &websocket.Services{
+		Slide:       slideService,
+		Pres:        presService,
+		Group:       groupService,
+		User:        userService,
+		Question:    questionService,
+		Message:     messageService,
+		Quiz:        quizService,
+		Session:     sessionService,
+		JWT:         jwtService,
+		Participant: participantService,
+	}
+	hub = websocket.NewHub(wsServices)
+
+	authController     = controller.NewAuthHandler(logger, jwtService, authService, mailService)
+	oauthController    = controller.NewOauthController(logger, jwtService, authService)
+	userController     = controller.NewUserController(logger, jwtService, userService, groupService)
+	groupController    = controller.NewGroupController(logger, jwtService, groupService, userService, authService, mailService)
+	presController     = controller.NewPresController(logger, jwtService, presService, userService)
+	slideController    = controller.NewSlideController(logger, jwtService, slideService, sessionService, participantService, hub)
+	questionController = controller.NewQuestionController(logger, jwtService, questionService, presService, userService, participantService, hub)
+	messageController  = controller.NewMessageController(logger, messageService)
+	quizController     = controller.NewQuizController(logger, quizService, sessionService)
+	resultsController  = controller.NewResultsController(logger, resultsService, sessionService)
+	sessionController  = controller.NewSessionController(logger, sessionService, slideService)
 )
 
 // @securityDefinitions.apikey Token
@@ -102,6 +132,8 @@ func main() {
 		groupRoutes.POST("/:id/invite-member", middleware.AuthorizeJWT(jwtService, logger), groupController.InviteMember)
 	}
 
+	router.GET(fmt.Sprintf("%s/slide-types", api), slideController.GetSlideTypes)
+
 	presRoutes := router.Group(fmt.Sprintf("%s/presentation", api)).Use(middleware.AuthorizeJWT(jwtService, logger))
 	{
 		presRoutes.GET("/", presController.GetAllPresentations)
@@ -111,23 +143,43 @@ func main() {
 		presRoutes.PUT("/:id/edit", presController.UpdatePresentation)
 		presRoutes.DELETE("/delete/:id", presController.DeletePresentation)
 		presRoutes.POST("/:id/group-presentation", presController.PresentGroup)
+		presRoutes.POST("/:id/copy", slideController.CopyPresentation)
+		presRoutes.GET("/:id/export", slideController.ExportPresentation)
+		presRoutes.POST("/import", slideController.ImportPresentation)
+		presRoutes.POST("/import/markdown", slideController.ImportMarkdown)
 		presRoutes.GET("/:id/slides/get-all", slideController.GetAllSlides)
+		presRoutes.PUT("/:id/slides/reorder", slideController.ReorderSlides)
 		presRoutes.POST("/:id/slide/create", slideController.CreateSlide)
 		presRoutes.PUT("/:id/slide/:slide_id/edit", slideController.UpdateSlide)
+		presRoutes.POST("/:id/slide/:slide_id/duplicate", slideController.DuplicateSlide)
 		presRoutes.DELETE("/:id/slide/delete/:slide_id", slideController.DeleteSlide)
-		presRoutes.POST("/:id/vote/:content_id/submit", slideController.UpdateOptionVote)
+		presRoutes.POST("/:id/vote/:content_id/submit", slideController.SubmitVote)
+		presRoutes.GET("/:id/slide/:slide_id/heatmap", slideController.GetHeatmap)
+		presRoutes.GET("/:id/questions", questionController.GetQuestions)
+		presRoutes.POST("/:id/questions", questionController.AskQuestion)
+		presRoutes.POST("/:id/questions/:question_id/upvote", questionController.UpvoteQuestion)
+		presRoutes.PUT("/:id/questions/:question_id/answered", questionController.MarkAnswered)
+		presRoutes.GET("/:id/messages", messageController.GetMessages)
+		presRoutes.GET("/:id/leaderboard", quizController.GetLeaderboard)
+		presRoutes.GET("/:id/results/export", resultsController.ExportResults)
+		presRoutes.GET("/:id/sessions", sessionController.GetSessions)
+		presRoutes.GET("/:id/sessions/:session_id", sessionController.GetSession)
 	}
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	router.StaticFS("/public", http.Dir("templates"))
 
 	// start websocket server
-	hub := websocket.NewHub()
+	if ended, err := sessionService.EndStaleSessions(); err != nil {
+		logger.Error(err.Error())
+	} else if ended > 0 {
+		logger.Info("ended stale sessions: ", ended)
+	}
 	go hub.Run()
 	router.GET("/ws", func(c *gin.Context) {
 		roomId := c.Query("roomId")
 		logger.Info("room id: ", roomId)
-		websocket.ServeWs(slideService, roomId, hub, c.Writer, c.Request)
+		websocket.ServeWs(wsServices, roomId, hub, c.Writer, c.Request)
 	})
 
 	logger.Info("Listening and serving HTTP on :", appConfig.Port)
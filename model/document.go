@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// PresDocument is the portable form of a presentation, exported and imported
+// as JSON to move it between environments
+type PresDocument struct {
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Name       string           `json:"name"`
+	Slides     []*DocumentSlide `json:"slides"`
+}
+
+// DocumentSlide is a slide of a document, which lists them in presentation
+// order
+type DocumentSlide struct {
+	Type    uint     `json:"type"`
+	Content *Content `json:"content"`
+}
+
+// DocumentProblem is a validation error of an imported document, located by
+// the path of the offending field such as `slides[2].content`
+type DocumentProblem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type Message struct {
+	Id             uint      `json:"id,omitempty"`
+	UserId         uint      `json:"user_id,omitempty"`
+	PresentationId uint      `json:"presentation_id,omitempty"`
+	Message        string    `json:"message,omitempty" binding:"required"`
+	Username       string    `json:"username,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Question struct {
+	Id             uint      `json:"id,omitempty"`
+	UserId         uint      `json:"user_id,omitempty"`
+	PresentationId uint      `json:"presentation_id,omitempty"`
+	ParticipantId  string    `json:"-"`
+	Question       string    `json:"question,omitempty" binding:"required"`
+	TotalVotes     uint      `json:"total_votes"`
+	Username       string    `json:"username,omitempty"`
+	IsAnswered     bool      `json:"is_answered"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+}
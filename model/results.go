@@ -0,0 +1,31 @@
+package model
+
+// PresResults gathers the audience results of the slides of a presentation
+// which collect responses during one of its sessions, in presentation order
+type PresResults struct {
+	PresentationId uint            `json:"presentation_id"`
+	SessionId      uint            `json:"session_id,omitempty"`
+	Name           string          `json:"name"`
+	Slides         []*SlideResults `json:"slides"`
+}
+
+// SlideResults counts the responses of a slide per option, in the measure of
+// its type such as votes or points. Number is the place of the slide in the
+// presentation, from 1. Total is the sum of the counts, percentages are
+// shares of it
+type SlideResults struct {
+	SlideId  uint         `json:"slide_id"`
+	Number   int          `json:"number"`
+	Type     uint         `json:"type"`
+	TypeName string       `json:"type_name"`
+	Title    string       `json:"title"`
+	Measure  string       `json:"measure"`
+	Total    uint         `json:"total"`
+	Rows     []*ResultRow `json:"rows"`
+}
+
+type ResultRow struct {
+	Label      string  `json:"label"`
+	Count      uint    `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Session is a run of a presentation, from the moment it is started for a
+// room or a group until it is ended. The responses of the audience belong to
+// the session they were given in. EndedAt is nil while the session runs
+type Session struct {
+	Id             uint       `json:"id"`
+	PresentationId uint       `json:"presentation_id"`
+	GroupId        uint       `json:"group_id,omitempty"`
+	StartedAt      time.Time  `json:"started_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+}
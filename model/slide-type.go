@@ -0,0 +1,41 @@
+package model
+
+// Slide types, matching the ids of `question_types`
+const (
+	SlideTypeMultipleChoice uint = 1
+	SlideTypeWordCloud      uint = 2
+	SlideTypeOpenEnded      uint = 3
+	SlideTypeScales         uint = 4
+	SlideTypeRanking        uint = 5
+	SlideTypeSelectAnswer   uint = 6
+	SlideTypeTypeAnswer     uint = 7
+	SlideTypeHeading        uint = 8
+	SlideTypeParagraph      uint = 9
+	SlideTypeBullets        uint = 10
+	SlideTypeImage          uint = 11
+	SlideTypeVideo          uint = 12
+	SlideTypeBig            uint = 13
+	SlideTypeQuote          uint = 14
+	SlideTypeNumber         uint = 15
+	SlideTypeInstructions   uint = 16
+	SlideType100Points      uint = 17
+	SlideType2x2Grid        uint = 18
+	SlideTypeWhoWillWin     uint = 19
+	SlideTypePinOnImage     uint = 20
+)
+
+// SlideCategory groups the slide types of the catalogue, matching
+// `question_categories`
+type SlideCategory struct {
+	Id    uint         `json:"id"`
+	Name  string       `json:"name"`
+	Types []*SlideType `json:"types"`
+}
+
+// SlideType is a slide type of the catalogue, along with the JSON schema of
+// the content of its slides. Types without a schema cannot be created
+type SlideType struct {
+	Id     uint           `json:"id"`
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema,omitempty"`
+}
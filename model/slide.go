@@ -1,20 +1,67 @@
 package model
 
+import "time"
+
 type Slide struct {
 	Id             uint     `json:"id,omitempty"`
 	PresentationId uint     `json:"presentation_id,omitempty"`
 	Type           uint     `json:"type,omitempty"`
+	Position       int      `json:"position"`
 	Content        *Content `json:"content,omitempty"`
 }
 
 type Content struct {
-	Id        uint       `json:"id,omitempty"`
-	SlideId   uint       `json:"slide_id,omitempty"`
-	Title     string     `json:"title,omitempty"`
-	Meta      string     `json:"meta,omitempty"`
-	Options   []*Option  `json:"options,omitempty"`
-	Heading   *Heading   `json:"heading,omitempty"`
-	Paragraph *Paragraph ` json:"paragraph,omitempty"`
+	Id              uint       `json:"id,omitempty"`
+	SlideId         uint       `json:"slide_id,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	Meta            string     `json:"meta,omitempty"`
+	Image           string     `json:"image,omitempty"`
+	AllowVoteChange bool       `json:"allow_vote_change"`
+	TimeLimit       uint       `json:"time_limit,omitempty"`
+	Options         []*Option  `json:"options,omitempty"`
+	Heading         *Heading   `json:"heading,omitempty"`
+	Paragraph       *Paragraph ` json:"paragraph,omitempty"`
+	Scale           *Scale     `json:"scale,omitempty"`
+
+	// Sub-contents of the content slides, one per slide type
+	Bullets      *Bullets      `json:"bullets,omitempty"`
+	ImageSlide   *Image        `json:"image_slide,omitempty"`
+	Video        *Video        `json:"video,omitempty"`
+	Big          *Big          `json:"big,omitempty"`
+	Quote        *Quote        `json:"quote,omitempty"`
+	Number       *Number       `json:"number,omitempty"`
+	Instructions *Instructions `json:"instructions,omitempty"`
+
+	// How typed answers are graded, for type answer slides only
+	AnswerPolicy *AnswerPolicy `json:"answer_policy,omitempty"`
+
+	// Axes the items of a 2x2 grid slide are placed on
+	GridAxes *GridAxes `json:"grid_axes,omitempty"`
+
+	// Results of the slide, filled in depending on its type
+	Words        []*WordFrequency   `json:"words,omitempty"`
+	Answers      []*OpenEndedAnswer `json:"answers,omitempty"`
+	ScaleResults []*ScaleResult     `json:"scale_results,omitempty"`
+	Ranking      *RankingResult     `json:"ranking,omitempty"`
+	Points       *PointsResult      `json:"points,omitempty"`
+	GridResults  []*GridResult      `json:"grid_results,omitempty"`
+	Heatmap      *Heatmap           `json:"heatmap,omitempty"`
+	Prediction   *PredictionResult  `json:"prediction,omitempty"`
+
+	// Rows of the sub-content tables read along with the content, turned into
+	// sub-contents by the slide type
+	SubContents []*SubContent `json:"-"`
+}
+
+// SubContent is a row of one of the sub-content tables of a slide, such as an
+// option or a heading, in the shape they are all read in
+type SubContent struct {
+	Id         uint
+	Heading    string
+	SubHeading string
+	Image      string
+	TotalVotes uint
+	IsCorrect  bool
 }
 
 type Option struct {
@@ -23,6 +70,7 @@ type Option struct {
 	Image      string `json:"image,omitempty"`
 	ContentId  uint   `json:"content_id,omitempty"`
 	TotalVotes uint   `json:"total_votes"`
+	IsCorrect  bool   `json:"is_correct,omitempty"`
 }
 
 type Heading struct {
@@ -40,3 +88,268 @@ type Paragraph struct {
 	Image     string `json:"image,omitempty"`
 	ContentId uint   `json:"content_id,omitempty"`
 }
+
+type Bullets struct {
+	Id        uint     `json:"id,omitempty"`
+	Heading   string   `json:"heading,omitempty"`
+	Items     []string `json:"items"`
+	Image     string   `json:"image,omitempty"`
+	ContentId uint     `json:"content_id,omitempty"`
+}
+
+type Image struct {
+	Id        uint   `json:"id,omitempty"`
+	Heading   string `json:"heading,omitempty"`
+	Url       string `json:"url,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+// Video is played from StartTime, in seconds
+type Video struct {
+	Id        uint   `json:"id,omitempty"`
+	Heading   string `json:"heading,omitempty"`
+	Url       string `json:"url,omitempty"`
+	StartTime uint   `json:"start_time"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+type Big struct {
+	Id        uint   `json:"id,omitempty"`
+	Text      string `json:"text,omitempty"`
+	SubText   string `json:"sub_text,omitempty"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+type Quote struct {
+	Id        uint   `json:"id,omitempty"`
+	Quote     string `json:"quote,omitempty"`
+	Author    string `json:"author,omitempty"`
+	Image     string `json:"image,omitempty"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+// Number is shown big with its caption, it is text so that units such as
+// "42%" are kept
+type Number struct {
+	Id        uint   `json:"id,omitempty"`
+	Number    string `json:"number,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+type Instructions struct {
+	Id        uint   `json:"id,omitempty"`
+	Heading   string `json:"heading,omitempty"`
+	Text      string `json:"text,omitempty"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+type Vote struct {
+	Id            uint      `json:"id,omitempty"`
+	SessionId     uint      `json:"session_id,omitempty"`
+	ContentId     uint      `json:"content_id,omitempty"`
+	OptionId      uint      `json:"option_id,omitempty"`
+	ParticipantId string    `json:"participant_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+type WordCloudWord struct {
+	Id            uint      `json:"id,omitempty"`
+	SessionId     uint      `json:"session_id,omitempty"`
+	ContentId     uint      `json:"content_id,omitempty"`
+	ParticipantId string    `json:"participant_id,omitempty"`
+	Word          string    `json:"word,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
+
+type WordFrequency struct {
+	Word  string `json:"word"`
+	Count uint   `json:"count"`
+}
+
+type OpenEndedAnswer struct {
+	Id            uint      `json:"id,omitempty"`
+	SessionId     uint      `json:"session_id,omitempty"`
+	ContentId     uint      `json:"content_id,omitempty"`
+	ParticipantId string    `json:"-"`
+	Author        string    `json:"author,omitempty"`
+	Text          string    `json:"text,omitempty"`
+	IsHidden      bool      `json:"is_hidden"`
+	IsHighlighted bool      `json:"is_highlighted"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
+
+type AnswerModeration struct {
+	IsHidden      *bool `json:"is_hidden,omitempty"`
+	IsHighlighted *bool `json:"is_highlighted,omitempty"`
+}
+
+type Scale struct {
+	Id        uint   `json:"id,omitempty"`
+	MinValue  int    `json:"min_value"`
+	MaxValue  int    `json:"max_value"`
+	MinLabel  string `json:"min_label,omitempty"`
+	MaxLabel  string `json:"max_label,omitempty"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+type Rating struct {
+	OptionId uint `json:"option_id"`
+	Value    int  `json:"value"`
+}
+
+type RatingCount struct {
+	OptionId uint
+	Value    int
+	Count    uint
+}
+
+// ScaleResult aggregates the ratings of one statement. Histogram[i] counts
+// the ratings of value Scale.MinValue + i
+type ScaleResult struct {
+	OptionId  uint    `json:"option_id"`
+	Average   float64 `json:"average"`
+	Count     uint    `json:"count"`
+	Histogram []uint  `json:"histogram"`
+}
+
+type PositionCount struct {
+	OptionId uint
+	Position int
+	Count    uint
+}
+
+type BordaScore struct {
+	OptionId uint `json:"option_id"`
+	Score    uint `json:"score"`
+}
+
+// RankingResult holds the Borda score of every option of a ranking slide,
+// highest first
+type RankingResult struct {
+	Ballots uint          `json:"ballots"`
+	Scores  []*BordaScore `json:"scores"`
+}
+
+type Allocation struct {
+	OptionId uint `json:"option_id"`
+	Points   int  `json:"points"`
+}
+
+type OptionPoints struct {
+	OptionId uint    `json:"option_id"`
+	Total    uint    `json:"total"`
+	Average  float64 `json:"average"`
+}
+
+// PointsResult holds the points given to every option of a 100 points slide,
+// most points first. Averages are over all the ballots
+type PointsResult struct {
+	Budget  int             `json:"budget"`
+	Ballots uint            `json:"ballots"`
+	Totals  []*OptionPoints `json:"totals"`
+}
+
+type GridAxes struct {
+	Id        uint   `json:"id,omitempty"`
+	XLabel    string `json:"x_label,omitempty"`
+	XMin      int    `json:"x_min"`
+	XMax      int    `json:"x_max"`
+	YLabel    string `json:"y_label,omitempty"`
+	YMin      int    `json:"y_min"`
+	YMax      int    `json:"y_max"`
+	ContentId uint   `json:"content_id,omitempty"`
+}
+
+// GridPoint places an item of a 2x2 grid slide on both axes
+type GridPoint struct {
+	OptionId uint `json:"option_id,omitempty"`
+	X        int  `json:"x"`
+	Y        int  `json:"y"`
+}
+
+// GridResult holds the mean position of an item of a 2x2 grid slide and the
+// points it was placed at
+type GridResult struct {
+	OptionId uint         `json:"option_id"`
+	Count    uint         `json:"count"`
+	MeanX    float64      `json:"mean_x"`
+	MeanY    float64      `json:"mean_y"`
+	Points   []*GridPoint `json:"points"`
+}
+
+// Pin is dropped on the image of a slide, at coordinates normalized to the
+// image size from 0 to 1, from its top left corner
+type Pin struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Heatmap bins the pins of a slide in a Resolution x Resolution grid, where
+// Cells[row][column] counts the pins of a cell, rows going down the image
+type Heatmap struct {
+	Resolution int      `json:"resolution"`
+	Pins       uint     `json:"pins"`
+	Cells      [][]uint `json:"cells"`
+}
+
+// PredictionResult is the outcome of a who will win slide once the presenter
+// revealed the winner
+type PredictionResult struct {
+	WinnerOptionId uint      `json:"winner_option_id"`
+	ResolvedAt     time.Time `json:"resolved_at"`
+	Predictions    uint      `json:"predictions"`
+	Correct        uint      `json:"correct"`
+}
+
+// QuizResponse is the answer of a participant to a quiz question, scored on
+// correctness and speed
+type QuizResponse struct {
+	Id            uint      `json:"id,omitempty"`
+	SessionId     uint      `json:"session_id,omitempty"`
+	ContentId     uint      `json:"content_id,omitempty"`
+	ParticipantId string    `json:"-"`
+	Username      string    `json:"username,omitempty"`
+	OptionId      uint      `json:"option_id,omitempty"`
+	Answer        string    `json:"answer,omitempty"`
+	IsCorrect     bool      `json:"is_correct"`
+	Points        uint      `json:"points"`
+	ResponseMs    uint      `json:"response_ms"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
+
+type LeaderboardEntry struct {
+	Rank           uint   `json:"rank"`
+	ParticipantId  string `json:"-"`
+	Username       string `json:"username"`
+	Points         uint   `json:"points"`
+	CorrectAnswers uint   `json:"correct_answers"`
+	ResponseMs     uint   `json:"response_ms"`
+}
+
+// AnswerPolicy tells how the typed answers of a type answer slide match its
+// accepted answers: exactly, ignoring case and diacritics, or within an edit
+// distance of MaxDistance after ignoring case and diacritics
+type AnswerPolicy struct {
+	Id          uint   `json:"id,omitempty"`
+	Policy      string `json:"policy"`
+	MaxDistance int    `json:"max_distance,omitempty"`
+	ContentId   uint   `json:"content_id,omitempty"`
+}
+
+type AnswerCount struct {
+	OptionId  uint   `json:"option_id,omitempty"`
+	Answer    string `json:"answer,omitempty"`
+	IsCorrect bool   `json:"is_correct"`
+	Count     uint   `json:"count"`
+}
+
+// QuizTally sums up the responses to a quiz question for the presenter
+type QuizTally struct {
+	SlideId   uint           `json:"slide_id"`
+	Responses uint           `json:"responses"`
+	Correct   uint           `json:"correct"`
+	Answers   []*AnswerCount `json:"answers"`
+}
@@ -108,16 +108,16 @@ const (
 	stmtReplaceGroupPresentation = "REPLACE INTO `group_pres_infos` VALUES(?, ?, ?)"
 
 	stmtInsertSlide = "INSERT INTO `slides` " +
-		"(id, pres_id, slide_type) " +
-		"VALUES (?, ?, ?);"
+		"(id, pres_id, slide_type, position) " +
+		"VALUES (?, ?, ?, ?);"
 
 	stmtInsertContent = "INSERT INTO `contents` " +
-		"(id, slide_id, title, meta) " +
-		"VALUES (?, ?, ?, ?);"
+		"(id, slide_id, title, meta, image, allow_vote_change, time_limit) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?);"
 
 	stmtInsertOption = "INSERT INTO `options` " +
-		"(name, image, content_id) " +
-		"VALUES (?, ?, ?);"
+		"(name, image, is_correct, content_id) " +
+		"VALUES (?, ?, ?, ?);"
 
 	stmtInsertHeading = "INSERT INTO `headings` " +
 		"(heading, sub_heading, image, content_id) " +
@@ -132,11 +132,11 @@ const (
 		"WHERE pres_id = ? AND id = ?;"
 
 	stmtUpdateContent = "UPDATE `contents` " +
-		"SET title = ?, meta = ? " +
+		"SET title = ?, meta = ?, image = ?, allow_vote_change = ?, time_limit = ? " +
 		"WHERE slide_id = ?;"
 
 	stmtUpdateOption = "UPDATE `options` " +
-		"SET name = ?, image = ? " +
+		"SET name = ?, image = ?, is_correct = ? " +
 		"WHERE content_id = ? AND id = ?;"
 
 	stmtUpdateHeading = "UPDATE `headings` " +
@@ -147,43 +147,413 @@ const (
 		"SET heading = ?, text = ?, image = ? " +
 		"WHERE content_id = ? AND id = ?;"
 
-	stmtSelectAllSlides = "WITH `sub-contents`(id, heading, sub_heading, image, total_votes, content_id) AS " +
+	stmtInsertBullets = "INSERT INTO `bullets` " +
+		"(heading, items, image, content_id) " +
+		"VALUES (?, ?, ?, ?);"
+
+	stmtInsertImage = "INSERT INTO `images` " +
+		"(heading, url, caption, content_id) " +
+		"VALUES (?, ?, ?, ?);"
+
+	stmtInsertVideo = "INSERT INTO `videos` " +
+		"(heading, url, start_time, content_id) " +
+		"VALUES (?, ?, ?, ?);"
+
+	stmtInsertBig = "INSERT INTO `big_texts` " +
+		"(text, sub_text, content_id) " +
+		"VALUES (?, ?, ?);"
+
+	stmtInsertQuote = "INSERT INTO `quotes` " +
+		"(quote, author, image, content_id) " +
+		"VALUES (?, ?, ?, ?);"
+
+	stmtInsertNumber = "INSERT INTO `numbers` " +
+		"(number, caption, content_id) " +
+		"VALUES (?, ?, ?);"
+
+	stmtInsertInstructions = "INSERT INTO `instructions` " +
+		"(heading, text, content_id) " +
+		"VALUES (?, ?, ?);"
+
+	stmtUpdateBullets = "UPDATE `bullets` " +
+		"SET heading = ?, items = ?, image = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateImage = "UPDATE `images` " +
+		"SET heading = ?, url = ?, caption = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateVideo = "UPDATE `videos` " +
+		"SET heading = ?, url = ?, start_time = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateBig = "UPDATE `big_texts` " +
+		"SET text = ?, sub_text = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateQuote = "UPDATE `quotes` " +
+		"SET quote = ?, author = ?, image = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateNumber = "UPDATE `numbers` " +
+		"SET number = ?, caption = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateInstructions = "UPDATE `instructions` " +
+		"SET heading = ?, text = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtSelectSlideTypes = "SELECT qc.id, qc.name, qt.id, qt.name " +
+		"FROM `question_categories` qc " +
+		"JOIN `question_types` qt ON qt.question_cate_id = qc.id " +
+		"ORDER BY qc.id, qt.id;"
+
+	stmtSelectAllSlides = "WITH `sub-contents`(id, heading, sub_heading, image, total_votes, is_correct, content_id) AS " +
 		"( " +
-		"    SELECT o.id, o.name, '', o.image, o.total_votes, content_id " +
+		"    SELECT o.id, o.name, '', o.image, (SELECT COUNT(*) FROM `option_votes` v WHERE v.option_id = o.id AND v.session_id = ?), o.is_correct, o.content_id " +
 		"    FROM `options` o " +
 		"    UNION " +
-		"    SELECT h.id, h.heading, h.sub_heading, h.image, '', content_id " +
+		"    SELECT h.id, h.heading, h.sub_heading, h.image, '', FALSE, content_id " +
 		"    FROM `headings` h " +
 		"    UNION " +
-		"    SELECT p.id, p.heading, p.text, p.image, '', content_id " +
+		"    SELECT p.id, p.heading, p.text, p.image, '', FALSE, content_id " +
 		"    FROM `paragraphs` p " +
+		"    UNION " +
+		"    SELECT b.id, b.heading, b.items, b.image, '', FALSE, content_id " +
+		"    FROM `bullets` b " +
+		"    UNION " +
+		"    SELECT i.id, i.heading, i.caption, i.url, '', FALSE, content_id " +
+		"    FROM `images` i " +
+		"    UNION " +
+		"    SELECT v.id, v.heading, CAST(v.start_time AS CHAR), v.url, '', FALSE, content_id " +
+		"    FROM `videos` v " +
+		"    UNION " +
+		"    SELECT bt.id, bt.text, bt.sub_text, '', '', FALSE, content_id " +
+		"    FROM `big_texts` bt " +
+		"    UNION " +
+		"    SELECT q.id, q.quote, q.author, q.image, '', FALSE, content_id " +
+		"    FROM `quotes` q " +
+		"    UNION " +
+		"    SELECT n.id, n.number, n.caption, '', '', FALSE, content_id " +
+		"    FROM `numbers` n " +
+		"    UNION " +
+		"    SELECT ins.id, ins.heading, ins.text, '', '', FALSE, content_id " +
+		"    FROM `instructions` ins " +
 		") " +
-		"SELECT s.id, s.slide_type, c.id, c.title, c.meta, sc.id, sc.heading, sc.sub_heading, sc.image, sc.total_votes " +
+		"SELECT s.id, s.pres_id, s.slide_type, s.position, c.id, c.title, c.meta, c.image, c.allow_vote_change, c.time_limit, sc.id, sc.heading, sc.sub_heading, sc.image, sc.total_votes, sc.is_correct " +
 		"FROM slides s " +
 		"JOIN contents c on s.id = c.slide_id " +
-		"JOIN `sub-contents` sc on c.id = sc.content_id " +
-		"WHERE s.pres_id = ?;"
+		"LEFT JOIN `sub-contents` sc on c.id = sc.content_id " +
+		"WHERE s.pres_id = ? " +
+		"ORDER BY s.position, s.id, sc.id;"
 
-	stmtSelectSlideById = "WITH `sub-contents`(id, heading, sub_heading, image, total_votes, content_id) AS " +
+	stmtSelectSlideById = "WITH `sub-contents`(id, heading, sub_heading, image, total_votes, is_correct, content_id) AS " +
 		"( " +
-		"    SELECT o.id, o.name, '', o.image, o.total_votes, content_id " +
+		"    SELECT o.id, o.name, '', o.image, (SELECT COUNT(*) FROM `option_votes` v WHERE v.option_id = o.id AND v.session_id = ?), o.is_correct, o.content_id " +
 		"    FROM `options` o " +
 		"    UNION " +
-		"    SELECT h.id, h.heading, h.sub_heading, h.image, '', content_id " +
+		"    SELECT h.id, h.heading, h.sub_heading, h.image, '', FALSE, content_id " +
 		"    FROM `headings` h " +
 		"    UNION " +
-		"    SELECT p.id, p.heading, p.text, p.image, '', content_id " +
+		"    SELECT p.id, p.heading, p.text, p.image, '', FALSE, content_id " +
 		"    FROM `paragraphs` p " +
+		"    UNION " +
+		"    SELECT b.id, b.heading, b.items, b.image, '', FALSE, content_id " +
+		"    FROM `bullets` b " +
+		"    UNION " +
+		"    SELECT i.id, i.heading, i.caption, i.url, '', FALSE, content_id " +
+		"    FROM `images` i " +
+		"    UNION " +
+		"    SELECT v.id, v.heading, CAST(v.start_time AS CHAR), v.url, '', FALSE, content_id " +
+		"    FROM `videos` v " +
+		"    UNION " +
+		"    SELECT bt.id, bt.text, bt.sub_text, '', '', FALSE, content_id " +
+		"    FROM `big_texts` bt " +
+		"    UNION " +
+		"    SELECT q.id, q.quote, q.author, q.image, '', FALSE, content_id " +
+		"    FROM `quotes` q " +
+		"    UNION " +
+		"    SELECT n.id, n.number, n.caption, '', '', FALSE, content_id " +
+		"    FROM `numbers` n " +
+		"    UNION " +
+		"    SELECT ins.id, ins.heading, ins.text, '', '', FALSE, content_id " +
+		"    FROM `instructions` ins " +
 		") " +
-		"SELECT s.id, s.slide_type, c.id, c.title, c.meta, sc.id, sc.heading, sc.sub_heading, sc.image, sc.total_votes " +
+		"SELECT s.id, s.pres_id, s.slide_type, s.position, c.id, c.title, c.meta, c.image, c.allow_vote_change, c.time_limit, sc.id, sc.heading, sc.sub_heading, sc.image, sc.total_votes, sc.is_correct " +
 		"FROM slides s " +
 		"JOIN contents c on s.id = c.slide_id " +
-		"JOIN `sub-contents` sc on c.id = sc.content_id " +
-		"WHERE s.id = ?;"
+		"LEFT JOIN `sub-contents` sc on c.id = sc.content_id " +
+		"WHERE s.id = ? " +
+		"ORDER BY sc.id;"
+
+	stmtSelectVoteChangePolicy = "SELECT c.allow_vote_change " +
+		"FROM `options` o " +
+		"JOIN `contents` c ON o.content_id = c.id " +
+		"JOIN `slides` s ON c.slide_id = s.id " +
+		"WHERE o.id = ? AND c.id = ? AND s.slide_type = ?;"
+
+	stmtSelectVote = "SELECT id, session_id, content_id, option_id, participant_id, created_at, updated_at " +
+		"FROM `option_votes` " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtInsertVote = "INSERT IGNORE INTO `option_votes` " +
+		"(session_id, content_id, option_id, participant_id, created_at, updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?);"
+
+	stmtUpdateVote = "UPDATE `option_votes` " +
+		"SET option_id = ?, updated_at = ? " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtInsertWord = "INSERT INTO `word_cloud_words` " +
+		"(session_id, content_id, participant_id, word, created_at) " +
+		"VALUES (?, ?, ?, ?, ?);"
+
+	stmtLockContent = "SELECT id FROM `contents` WHERE id = ? FOR UPDATE;"
+
+	stmtCountParticipantWords = "SELECT COUNT(*) " +
+		"FROM `word_cloud_words` " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtSelectWordFrequencies = "SELECT word, COUNT(*) AS frequency " +
+		"FROM `word_cloud_words` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"GROUP BY word " +
+		"ORDER BY frequency DESC, word;"
+
+	stmtInsertAnswer = "INSERT INTO `open_ended_answers` " +
+		"(session_id, content_id, participant_id, author, text, is_hidden, is_highlighted, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+
+	stmtSelectAnswers = "SELECT id, content_id, participant_id, author, text, is_hidden, is_highlighted, created_at " +
+		"FROM `open_ended_answers` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"ORDER BY created_at;"
+
+	stmtSelectAnswerById = "SELECT id, content_id, participant_id, author, text, is_hidden, is_highlighted, created_at " +
+		"FROM `open_ended_answers` " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtUpdateAnswerModeration = "UPDATE `open_ended_answers` " +
+		"SET is_hidden = ?, is_highlighted = ? " +
+		"WHERE content_id = ? AND id = ?;"
+
+	stmtInsertScale = "INSERT INTO `scales` " +
+		"(min_value, max_value, min_label, max_label, content_id) " +
+		"VALUES (?, ?, ?, ?, ?);"
+
+	stmtSelectScale = "SELECT id, min_value, max_value, min_label, max_label, content_id " +
+		"FROM `scales` " +
+		"WHERE content_id = ?;"
+
+	stmtUpdateScale = "UPDATE `scales` " +
+		"SET min_value = ?, max_value = ?, min_label = ?, max_label = ? " +
+		"WHERE content_id = ?;"
+
+	stmtUpsertAnswerPolicy = "INSERT INTO `answer_policies` " +
+		"(policy, max_distance, content_id) " +
+		"VALUES (?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE policy = VALUES(policy), max_distance = VALUES(max_distance);"
+
+	stmtSelectAnswerPolicy = "SELECT id, policy, max_distance, content_id " +
+		"FROM `answer_policies` " +
+		"WHERE content_id = ?;"
+
+	stmtSelectOptionIds = "SELECT id FROM `options` WHERE content_id = ?;"
+
+	stmtCountParticipantRatings = "SELECT COUNT(*) " +
+		"FROM `scale_ratings` " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtUpsertRating = "INSERT INTO `scale_ratings` " +
+		"(session_id, content_id, option_id, participant_id, value, created_at, updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE value = VALUES(value), updated_at = VALUES(updated_at);"
+
+	stmtSelectRatingCounts = "SELECT option_id, value, COUNT(*) " +
+		"FROM `scale_ratings` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"GROUP BY option_id, value;"
+
+	stmtSelectAllowVoteChange = "SELECT allow_vote_change FROM `contents` WHERE id = ?;"
+
+	stmtCountParticipantPositions = "SELECT COUNT(*) " +
+		"FROM `ranking_positions` " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtDeleteParticipantPositions = "DELETE FROM `ranking_positions` WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtInsertPosition = "INSERT INTO `ranking_positions` " +
+		"(session_id, content_id, option_id, participant_id, position, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?);"
+
+	stmtSelectPositionCounts = "SELECT option_id, position, COUNT(*) " +
+		"FROM `ranking_positions` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"GROUP BY option_id, position;"
+
+	stmtCountBallots = "SELECT COUNT(DISTINCT participant_id) " +
+		"FROM `ranking_positions` " +
+		"WHERE session_id = ? AND content_id = ?;"
+
+	stmtCountParticipantAllocations = "SELECT COUNT(*) " +
+		"FROM `point_allocations` " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtDeleteParticipantAllocations = "DELETE FROM `point_allocations` WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtInsertAllocation = "INSERT INTO `point_allocations` " +
+		"(session_id, content_id, option_id, participant_id, points, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?);"
+
+	stmtSelectPointTotals = "SELECT option_id, SUM(points) " +
+		"FROM `point_allocations` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"GROUP BY option_id;"
+
+	stmtCountAllocationBallots = "SELECT COUNT(DISTINCT participant_id) " +
+		"FROM `point_allocations` " +
+		"WHERE session_id = ? AND content_id = ?;"
+
+	stmtUpsertGridAxes = "INSERT INTO `grid_axes` " +
+		"(x_label, x_min, x_max, y_label, y_min, y_max, content_id) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE x_label = VALUES(x_label), x_min = VALUES(x_min), x_max = VALUES(x_max), " +
+		"y_label = VALUES(y_label), y_min = VALUES(y_min), y_max = VALUES(y_max);"
+
+	stmtSelectGridAxes = "SELECT id, x_label, x_min, x_max, y_label, y_min, y_max, content_id " +
+		"FROM `grid_axes` " +
+		"WHERE content_id = ?;"
+
+	stmtCountParticipantGridPoints = "SELECT COUNT(*) " +
+		"FROM `grid_points` " +
+		"WHERE session_id = ? AND content_id = ? AND participant_id = ?;"
+
+	stmtUpsertGridPoint = "INSERT INTO `grid_points` " +
+		"(session_id, content_id, option_id, participant_id, x, y, created_at, updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE x = VALUES(x), y = VALUES(y), updated_at = VALUES(updated_at);"
 
-	stmtUpdateOptionVote = "UPDATE `options` " +
-		"SET `total_votes` = `total_votes` + 1 " +
-		"WHERE id = ? AND content_id = ?;"
+	stmtSelectGridPoints = "SELECT option_id, x, y " +
+		"FROM `grid_points` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"ORDER BY id;"
+
+	stmtUpsertPin = "INSERT INTO `image_pins` " +
+		"(session_id, content_id, participant_id, x, y, created_at, updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE x = VALUES(x), y = VALUES(y), updated_at = VALUES(updated_at);"
+
+	stmtSelectPins = "SELECT x, y FROM `image_pins` WHERE session_id = ? AND content_id = ?;"
+
+	stmtUpsertPredictionResolution = "INSERT INTO `prediction_resolutions` " +
+		"(session_id, content_id, option_id, resolved_at) " +
+		"VALUES (?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE option_id = VALUES(option_id), resolved_at = VALUES(resolved_at);"
+
+	stmtDeletePredictionScores = "DELETE FROM `prediction_scores` WHERE session_id = ? AND content_id = ?;"
+
+	stmtInsertPredictionScores = "INSERT INTO `prediction_scores` " +
+		"(session_id, content_id, participant_id, option_id, is_correct, points) " +
+		"SELECT session_id, content_id, participant_id, option_id, option_id = ?, IF(option_id = ?, ?, 0) " +
+		"FROM `option_votes` " +
+		"WHERE session_id = ? AND content_id = ?;"
+
+	stmtSelectPredictionResult = "SELECT r.option_id, r.resolved_at, " +
+		"(SELECT COUNT(*) FROM `prediction_scores` ps WHERE ps.content_id = r.content_id AND ps.session_id = r.session_id), " +
+		"(SELECT COUNT(*) FROM `prediction_scores` ps WHERE ps.content_id = r.content_id AND ps.session_id = r.session_id AND ps.is_correct) " +
+		"FROM `prediction_resolutions` r " +
+		"WHERE r.session_id = ? AND r.content_id = ?;"
+
+	stmtLockPresentation = "SELECT id FROM `presentations` WHERE id = ? FOR UPDATE;"
+
+	stmtSelectSlideIds = "SELECT id FROM `slides` WHERE pres_id = ? ORDER BY position, id;"
+
+	stmtUpdateSlidePosition = "UPDATE `slides` SET position = ? WHERE pres_id = ? AND id = ?;"
 
 	stmtDeleteSlideById = "DELETE FROM `slides` WHERE pres_id = ? AND id = ?;"
+
+	stmtInsertQuestion = "INSERT INTO `questions` " +
+		"(user_id, present_id, participant_id, question, username, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?);"
+
+	stmtSelectQuestionsByVotes = "SELECT id, user_id, present_id, question, total_votes, username, is_answered, created_at " +
+		"FROM `questions` " +
+		"WHERE present_id = ? " +
+		"ORDER BY total_votes DESC, created_at DESC;"
+
+	stmtSelectQuestionsByRecency = "SELECT id, user_id, present_id, question, total_votes, username, is_answered, created_at " +
+		"FROM `questions` " +
+		"WHERE present_id = ? " +
+		"ORDER BY created_at DESC;"
+
+	stmtSelectQuestionById = "SELECT id, user_id, present_id, question, total_votes, username, is_answered, created_at " +
+		"FROM `questions` " +
+		"WHERE present_id = ? AND id = ?;"
+
+	stmtInsertQuestionVote = "INSERT IGNORE INTO `question_user` " +
+		"(user_id, present_id, question_id, participant_id, created_at) " +
+		"VALUES (?, ?, ?, ?, ?);"
+
+	stmtIncreaseQuestionVotes = "UPDATE `questions` " +
+		"SET total_votes = total_votes + 1 " +
+		"WHERE present_id = ? AND id = ?;"
+
+	stmtUpdateQuestionAnswered = "UPDATE `questions` " +
+		"SET is_answered = ? " +
+		"WHERE present_id = ? AND id = ?;"
+
+	stmtInsertMessage = "INSERT INTO `messages` " +
+		"(user_id, present_id, message, username, created_at) " +
+		"VALUES (?, ?, ?, ?, ?);"
+
+	stmtSelectMessagesBefore = "SELECT id, user_id, present_id, message, username, created_at " +
+		"FROM `messages` " +
+		"WHERE present_id = ? AND (? = 0 OR id < ?) " +
+		"ORDER BY id DESC " +
+		"LIMIT ?;"
+
+	stmtInsertQuizResponse = "INSERT IGNORE INTO `quiz_responses` " +
+		"(session_id, content_id, participant_id, username, option_id, answer, is_correct, points, response_ms, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+
+	stmtSelectLeaderboard = "SELECT r.participant_id, MAX(r.username), SUM(r.points), SUM(r.is_correct), SUM(r.response_ms) " +
+		"FROM `quiz_responses` r " +
+		"JOIN `contents` c ON r.content_id = c.id " +
+		"JOIN `slides` s ON c.slide_id = s.id " +
+		"WHERE s.pres_id = ? AND r.session_id = ? " +
+		"GROUP BY r.participant_id " +
+		"ORDER BY SUM(r.points) DESC, SUM(r.response_ms) ASC " +
+		"LIMIT ?;"
+
+	stmtSelectQuizTally = "SELECT COALESCE(option_id, 0), answer, is_correct, COUNT(*) " +
+		"FROM `quiz_responses` " +
+		"WHERE session_id = ? AND content_id = ? " +
+		"GROUP BY option_id, answer, is_correct;"
+
+	stmtInsertSession = "INSERT INTO `presentation_sessions` " +
+		"(pres_id, group_id, started_at) " +
+		"VALUES (?, ?, ?);"
+
+	stmtEndSessions = "UPDATE `presentation_sessions` " +
+		"SET ended_at = ? " +
+		"WHERE pres_id = ? AND ended_at IS NULL;"
+
+	stmtEndAllSessions = "UPDATE `presentation_sessions` " +
+		"SET ended_at = ? " +
+		"WHERE ended_at IS NULL;"
+
+	stmtSelectSessions = "SELECT id, pres_id, group_id, started_at, ended_at " +
+		"FROM `presentation_sessions` " +
+		"WHERE pres_id = ? " +
+		"ORDER BY started_at DESC, id DESC;"
+
+	stmtSelectSessionById = "SELECT id, pres_id, group_id, started_at, ended_at " +
+		"FROM `presentation_sessions` " +
+		"WHERE pres_id = ? AND id = ?;"
+
+	stmtSelectLatestSession = "SELECT id, pres_id, group_id, started_at, ended_at " +
+		"FROM `presentation_sessions` " +
+		"WHERE pres_id = ? " +
+		"ORDER BY started_at DESC, id DESC " +
+		"LIMIT 1;"
 )
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"advanced-webapp-project/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type IMessageRepo interface {
+	InsertMessage(message *model.Message) error
+	FindMessagesBefore(presId string, beforeId uint, limit int) ([]*model.Message, error)
+}
+
+type messageRepo struct {
+	conn *sql.DB
+}
+
+func NewMessageRepo(sqldb *sql.DB) *messageRepo {
+	return &messageRepo{
+		conn: sqldb,
+	}
+}
+
+func (db *messageRepo) InsertMessage(message *model.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	message.CreatedAt = time.Now()
+	res, err := db.conn.ExecContext(ctx, stmtInsertMessage,
+		nullableId(message.UserId),
+		message.PresentationId,
+		message.Message,
+		message.Username,
+		message.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	message.Id = uint(id)
+
+	return nil
+}
+
+// FindMessagesBefore lists the latest messages of a presentation older than
+// beforeId, newest first. A zero beforeId starts from the latest message
+func (db *messageRepo) FindMessagesBefore(presId string, beforeId uint, limit int) ([]*model.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectMessagesBefore, presId, beforeId, beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*model.Message
+	for rows.Next() {
+		var message model.Message
+		var userId sql.NullInt64
+		var createdAt sql.NullTime
+		if err = rows.Scan(
+			&message.Id,
+			&userId,
+			&message.PresentationId,
+			&message.Message,
+			&message.Username,
+			&createdAt,
+		); err != nil {
+			return nil, err
+		}
+		message.UserId = uint(userId.Int64)
+		message.CreatedAt = createdAt.Time
+		messages = append(messages, &message)
+	}
+
+	return messages, rows.Err()
+}
@@ -19,7 +19,7 @@ type IPresRepo interface {
 }
 
 type presRepo struct {
-	conn *sql.DB
+	conn conn
 }
 
 func NewPresRepo(sqldb *sql.DB) *presRepo {
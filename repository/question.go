@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type IQuestionRepo interface {
+	InsertQuestion(question *model.Question) error
+	FindQuestionsByVotes(presId string) ([]*model.Question, error)
+	FindQuestionsByRecency(presId string) ([]*model.Question, error)
+	FindQuestionById(presId, questionId string) (*model.Question, error)
+	InsertQuestionVote(presId, questionId, userId, participantId string) (int64, error)
+	IncreaseQuestionVotes(presId, questionId string) (int64, error)
+	UpdateQuestionAnswered(presId, questionId string, answered bool) (int64, error)
+}
+
+type questionRepo struct {
+	conn conn
+}
+
+func NewQuestionRepo(sqldb *sql.DB) *questionRepo {
+	return &questionRepo{
+		conn: sqldb,
+	}
+}
+
+func (db *questionRepo) InsertQuestion(question *model.Question) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	question.CreatedAt = time.Now()
+	res, err := db.conn.ExecContext(ctx, stmtInsertQuestion,
+		nullableId(question.UserId),
+		question.PresentationId,
+		question.ParticipantId,
+		question.Question,
+		question.Username,
+		question.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	question.Id = uint(id)
+
+	return nil
+}
+
+func (db *questionRepo) FindQuestionsByVotes(presId string) ([]*model.Question, error) {
+	return db.findQuestions(stmtSelectQuestionsByVotes, presId)
+}
+
+func (db *questionRepo) FindQuestionsByRecency(presId string) ([]*model.Question, error) {
+	return db.findQuestions(stmtSelectQuestionsByRecency, presId)
+}
+
+func (db *questionRepo) findQuestions(stmt, presId string) ([]*model.Question, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmt, presId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*model.Question
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+func (db *questionRepo) FindQuestionById(presId, questionId string) (*model.Question, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	return scanQuestion(db.conn.QueryRowContext(ctx, stmtSelectQuestionById, presId, questionId))
+}
+
+// InsertQuestionVote records the upvote of a participant, returning 0 when
+// the participant already upvoted the question
+func (db *questionRepo) InsertQuestionVote(presId, questionId, userId, participantId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtInsertQuestionVote, nullableId(utils.Str2Uint(userId)), presId, questionId, participantId, time.Now())
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *questionRepo) IncreaseQuestionVotes(presId, questionId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtIncreaseQuestionVotes, presId, questionId)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *questionRepo) UpdateQuestionAnswered(presId, questionId string, answered bool) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateQuestionAnswered, answered, presId, questionId)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuestion(row rowScanner) (*model.Question, error) {
+	var question model.Question
+	var userId sql.NullInt64
+	if err := row.Scan(
+		&question.Id,
+		&userId,
+		&question.PresentationId,
+		&question.Question,
+		&question.TotalVotes,
+		&question.Username,
+		&question.IsAnswered,
+		&question.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	question.UserId = uint(userId.Int64)
+
+	return &question, nil
+}
+
+// nullableId stores a zero id, such as the user id of an anonymous
+// participant, as NULL
+func nullableId(id uint) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"advanced-webapp-project/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type IQuizRepo interface {
+	InsertQuizResponse(response *model.QuizResponse) (int64, error)
+	FindLeaderboard(presId, sessionId string, limit int) ([]*model.LeaderboardEntry, error)
+	FindQuizTally(sessionId string, contentId uint) ([]*model.AnswerCount, error)
+}
+
+type quizRepo struct {
+	conn *sql.DB
+}
+
+func NewQuizRepo(sqldb *sql.DB) *quizRepo {
+	return &quizRepo{
+		conn: sqldb,
+	}
+}
+
+// InsertQuizResponse records the answer of a participant, returning 0 when
+// the participant already answered the question
+func (db *quizRepo) InsertQuizResponse(response *model.QuizResponse) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	response.CreatedAt = time.Now()
+	res, err := db.conn.ExecContext(ctx, stmtInsertQuizResponse,
+		response.SessionId,
+		response.ContentId,
+		response.ParticipantId,
+		response.Username,
+		nullableId(response.OptionId),
+		response.Answer,
+		response.IsCorrect,
+		response.Points,
+		response.ResponseMs,
+		response.CreatedAt,
+	)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+// FindLeaderboard ranks the participants of the quizzes of a presentation
+// during a session by points, the fastest first on equal points
+func (db *quizRepo) FindLeaderboard(presId, sessionId string, limit int) ([]*model.LeaderboardEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectLeaderboard, presId, sessionId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaderboard []*model.LeaderboardEntry
+	for rows.Next() {
+		var entry model.LeaderboardEntry
+		if err = rows.Scan(
+			&entry.ParticipantId,
+			&entry.Username,
+			&entry.Points,
+			&entry.CorrectAnswers,
+			&entry.ResponseMs,
+		); err != nil {
+			return nil, err
+		}
+		entry.Rank = uint(len(leaderboard) + 1)
+		leaderboard = append(leaderboard, &entry)
+	}
+
+	return leaderboard, rows.Err()
+}
+
+// FindQuizTally counts the responses to a quiz question by picked option or
+// typed answer
+func (db *quizRepo) FindQuizTally(sessionId string, contentId uint) ([]*model.AnswerCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectQuizTally, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*model.AnswerCount
+	for rows.Next() {
+		var count model.AnswerCount
+		if err = rows.Scan(&count.OptionId, &count.Answer, &count.IsCorrect, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"advanced-webapp-project/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type ISessionRepo interface {
+	InsertSession(session *model.Session) error
+	EndSessions(presId string) (int64, error)
+	EndAllSessions() (int64, error)
+	FindSessions(presId string) ([]*model.Session, error)
+	FindSessionById(presId, sessionId string) (*model.Session, error)
+	FindLatestSession(presId string) (*model.Session, error)
+}
+
+type sessionRepo struct {
+	conn conn
+}
+
+func NewSessionRepo(sqldb *sql.DB) *sessionRepo {
+	return &sessionRepo{
+		conn: sqldb,
+	}
+}
+
+func (db *sessionRepo) InsertSession(session *model.Session) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	session.StartedAt = time.Now()
+	res, err := db.conn.ExecContext(ctx, stmtInsertSession,
+		session.PresentationId,
+		nullableId(session.GroupId),
+		session.StartedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	session.Id = uint(id)
+
+	return nil
+}
+
+// EndSessions ends the sessions of a presentation still running
+func (db *sessionRepo) EndSessions(presId string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtEndSessions, time.Now(), presId)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+// EndAllSessions ends the sessions of every presentation still running
+func (db *sessionRepo) EndAllSessions() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtEndAllSessions, time.Now())
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+// FindSessions lists the sessions of a presentation, latest first
+func (db *sessionRepo) FindSessions(presId string) ([]*model.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectSessions, presId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*model.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (db *sessionRepo) FindSessionById(presId, sessionId string) (*model.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	return scanSession(db.conn.QueryRowContext(ctx, stmtSelectSessionById, presId, sessionId))
+}
+
+func (db *sessionRepo) FindLatestSession(presId string) (*model.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	return scanSession(db.conn.QueryRowContext(ctx, stmtSelectLatestSession, presId))
+}
+
+// scanSession reads a session out of a row of the session statements, which
+// is either a *sql.Row or the current row of *sql.Rows
+func scanSession(row interface{ Scan(dest ...any) error }) (*model.Session, error) {
+	var session model.Session
+	var groupId sql.NullInt64
+	var startedAt, endedAt sql.NullTime
+	if err := row.Scan(&session.Id, &session.PresentationId, &groupId, &startedAt, &endedAt); err != nil {
+		return nil, err
+	}
+
+	session.GroupId = uint(groupId.Int64)
+	session.StartedAt = startedAt.Time
+	if endedAt.Valid {
+		session.EndedAt = &endedAt.Time
+	}
+	return &session, nil
+}
@@ -5,27 +5,85 @@ import (
 	"advanced-webapp-project/utils"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 )
 
 type ISlideRepo interface {
-	FindAllSlides(presId string) ([]*model.Slide, error)
-	FindSlideById(slideId string) (*model.Slide, error)
+	FindAllSlides(presId, sessionId string) ([]*model.Slide, error)
+	FindSlideTypes() ([]*model.SlideCategory, error)
+	FindSlideById(slideId, sessionId string) (*model.Slide, error)
 	InsertSlide(slide *model.Slide) error
+	LockPresentation(presId string) error
+	FindSlideIds(presId string) ([]uint, error)
+	UpdateSlidePositions(presId string, slideIds []uint) error
 	InsertContent(slideId string, content *model.Content) error
 	InsertOption(contentId string, options []*model.Option) error
 	InsertHeading(contentId string, heading *model.Heading) error
 	InsertParagraph(contentId string, paragraph *model.Paragraph) error
+	InsertBullets(contentId string, bullets *model.Bullets) error
+	InsertImage(contentId string, image *model.Image) error
+	InsertVideo(contentId string, video *model.Video) error
+	InsertBig(contentId string, big *model.Big) error
+	InsertQuote(contentId string, quote *model.Quote) error
+	InsertNumber(contentId string, number *model.Number) error
+	InsertInstructions(contentId string, instructions *model.Instructions) error
 	UpdateSlide(presId string, slide model.Slide) (int64, error)
 	UpdateContent(slideId string, content model.Content) (int64, error)
 	UpdateOptions(contentId string, options []*model.Option) (int64, error)
-	UpdateOptionVote(contentId string, optionId string) (int64, error)
+	FindVoteChangePolicy(slideType uint, contentId, optionId string) (bool, error)
+	FindVote(sessionId, contentId, participantId string) (*model.Vote, error)
+	InsertVote(vote *model.Vote) (int64, error)
+	UpdateVote(vote *model.Vote) (int64, error)
+	InsertWord(word *model.WordCloudWord) error
+	LockContent(contentId string) error
+	CountParticipantWords(sessionId, contentId, participantId string) (int, error)
+	FindWordFrequencies(sessionId, contentId string) ([]*model.WordFrequency, error)
+	InsertAnswer(answer *model.OpenEndedAnswer) error
+	FindAnswers(sessionId, contentId string) ([]*model.OpenEndedAnswer, error)
+	FindAnswerById(contentId, answerId string) (*model.OpenEndedAnswer, error)
+	UpdateAnswerModeration(answer *model.OpenEndedAnswer) (int64, error)
+	InsertScale(contentId string, scale *model.Scale) error
+	FindScale(contentId string) (*model.Scale, error)
+	UpdateScale(contentId string, scale *model.Scale) (int64, error)
+	UpsertAnswerPolicy(contentId string, policy *model.AnswerPolicy) error
+	FindAnswerPolicy(contentId string) (*model.AnswerPolicy, error)
+	FindOptionIds(contentId string) ([]uint, error)
+	HasRatings(sessionId, contentId, participantId string) (bool, error)
+	UpsertRatings(sessionId, contentId, participantId string, ratings []*model.Rating) error
+	FindRatingCounts(sessionId, contentId string) ([]*model.RatingCount, error)
+	FindAllowVoteChange(contentId string) (bool, error)
+	HasRankingBallot(sessionId, contentId, participantId string) (bool, error)
+	ReplaceRankingBallot(sessionId, contentId, participantId string, ranking []uint) error
+	HasAllocation(sessionId, contentId, participantId string) (bool, error)
+	ReplaceAllocation(sessionId, contentId, participantId string, allocations []*model.Allocation) error
+	FindPointTotals(sessionId, contentId string) ([]*model.OptionPoints, error)
+	CountAllocationBallots(sessionId, contentId string) (uint, error)
+	UpsertGridAxes(contentId string, axes *model.GridAxes) error
+	FindGridAxes(contentId string) (*model.GridAxes, error)
+	HasGridPoints(sessionId, contentId, participantId string) (bool, error)
+	UpsertGridPoints(sessionId, contentId, participantId string, points []*model.GridPoint) error
+	FindGridPoints(sessionId, contentId string) ([]*model.GridPoint, error)
+	UpsertPin(sessionId, contentId, participantId string, pin *model.Pin) error
+	FindPins(sessionId, contentId string) ([]*model.Pin, error)
+	ResolvePrediction(sessionId, contentId, optionId string, points int) error
+	FindPredictionResult(sessionId, contentId string) (*model.PredictionResult, error)
+	FindPositionCounts(sessionId, contentId string) ([]*model.PositionCount, error)
+	CountBallots(sessionId, contentId string) (uint, error)
 	UpdateHeading(contentId string, heading *model.Heading) (int64, error)
 	UpdateParagraph(contentId string, paragraph *model.Paragraph) (int64, error)
+	UpdateBullets(contentId string, bullets *model.Bullets) (int64, error)
+	UpdateImage(contentId string, image *model.Image) (int64, error)
+	UpdateVideo(contentId string, video *model.Video) (int64, error)
+	UpdateBig(contentId string, big *model.Big) (int64, error)
+	UpdateQuote(contentId string, quote *model.Quote) (int64, error)
+	UpdateNumber(contentId string, number *model.Number) (int64, error)
+	UpdateInstructions(contentId string, instructions *model.Instructions) (int64, error)
 	DeleteSlide(presId, slideId string) (int64, error)
 }
 
 type slideRepo struct {
-	conn *sql.DB
+	conn conn
 }
 
 func NewSlideRepo(sqldb *sql.DB) *slideRepo {
@@ -34,163 +92,149 @@ func NewSlideRepo(sqldb *sql.DB) *slideRepo {
 	}
 }
 
-func (db *slideRepo) FindAllSlides(presId string) ([]*model.Slide, error) {
+func (db *slideRepo) FindAllSlides(presId, sessionId string) ([]*model.Slide, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	rows, err := db.conn.QueryContext(ctx, stmtSelectAllSlides, presId)
+	rows, err := db.conn.QueryContext(ctx, stmtSelectAllSlides, sessionId, presId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	type subContent struct {
-		Id         uint
-		Heading    string
-		SubHeading string
-		Image      string
-		TotalVotes string
+	return scanSlides(rows)
+}
+
+// FindSlideTypes reads the catalogue of slide types, grouped by category
+func (db *slideRepo) FindSlideTypes() ([]*model.SlideCategory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectSlideTypes)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	var slides []*model.Slide
+	var categories []*model.SlideCategory
 	for rows.Next() {
-		var slide model.Slide
-		var content model.Content
-		var option model.Option
-		var heading model.Heading
-		var paragraph model.Paragraph
-		var sc subContent
-		var index = len(slides) - 1
-		if err = rows.Scan(
-			&slide.Id,
-			&slide.Type,
-			&content.Id,
-			&content.Title,
-			&content.Meta,
-			&sc.Id,
-			&sc.Heading,
-			&sc.SubHeading,
-			&sc.Image,
-			&sc.TotalVotes,
-		); err != nil {
+		var category model.SlideCategory
+		var slideType model.SlideType
+		if err := rows.Scan(&category.Id, &category.Name, &slideType.Id, &slideType.Name); err != nil {
 			return nil, err
 		}
 
-		switch slide.Type {
-		case 1:
-			option.Id = sc.Id
-			option.Name = sc.Heading
-			option.Image = sc.Image
-			option.TotalVotes = utils.Str2Uint(sc.TotalVotes)
-			content.Options = append(content.Options, &option)
-		case 8:
-			heading.Id = sc.Id
-			heading.Heading = sc.Heading
-			heading.SubHeading = sc.SubHeading
-			heading.Image = sc.Image
-			content.Heading = &heading
-		case 9:
-			paragraph.Id = sc.Id
-			paragraph.Heading = sc.Heading
-			paragraph.Text = sc.SubHeading
-			paragraph.Image = sc.Image
-			content.Paragraph = &paragraph
-		default:
-		}
-
-		slide.Content = &content
-		if len(slides) == 0 {
-			slides = append(slides, &slide)
-		} else if slides[index].Id == slide.Id {
-			slides[index].Content.Options = append(slides[index].Content.Options, &option)
-		} else {
-			slides = append(slides, &slide)
+		if n := len(categories); n == 0 || categories[n-1].Id != category.Id {
+			categories = append(categories, &category)
 		}
+		current := categories[len(categories)-1]
+		current.Types = append(current.Types, &slideType)
 	}
 
-	return slides, nil
+	return categories, rows.Err()
 }
 
-func (db *slideRepo) FindSlideById(slideId string) (*model.Slide, error) {
+func (db *slideRepo) FindSlideById(slideId, sessionId string) (*model.Slide, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	rows, err := db.conn.QueryContext(ctx, stmtSelectSlideById, slideId)
+	rows, err := db.conn.QueryContext(ctx, stmtSelectSlideById, sessionId, slideId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	slides, err := scanSlides(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(slides) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return slides[0], nil
+}
+
+// scanSlides builds slides out of the rows of stmtSelectAllSlides or
+// stmtSelectSlideById, one row per sub-content of a slide. Slides without
+// sub-content, such as word clouds, come as a single row of NULL sub-content
+func scanSlides(rows *sql.Rows) ([]*model.Slide, error) {
 	type subContent struct {
-		Id         uint
-		Heading    string
-		SubHeading string
-		Image      string
-		TotalVotes string
+		Id         sql.NullInt64
+		Heading    sql.NullString
+		SubHeading sql.NullString
+		Image      sql.NullString
+		TotalVotes sql.NullString
+		IsCorrect  sql.NullBool
 	}
 
 	var slides []*model.Slide
+	slidesById := make(map[uint]*model.Slide)
 	for rows.Next() {
 		var slide model.Slide
 		var content model.Content
-		var option model.Option
-		var heading model.Heading
-		var paragraph model.Paragraph
 		var sc subContent
-		var index = len(slides) - 1
-		if err = rows.Scan(
+		if err := rows.Scan(
 			&slide.Id,
+			&slide.PresentationId,
 			&slide.Type,
+			&slide.Position,
 			&content.Id,
 			&content.Title,
 			&content.Meta,
+			&content.Image,
+			&content.AllowVoteChange,
+			&content.TimeLimit,
 			&sc.Id,
 			&sc.Heading,
 			&sc.SubHeading,
 			&sc.Image,
 			&sc.TotalVotes,
+			&sc.IsCorrect,
 		); err != nil {
 			return nil, err
 		}
 
-		switch slide.Type {
-		case 1:
-			option.Id = sc.Id
-			option.Name = sc.Heading
-			option.Image = sc.Image
-			option.TotalVotes = utils.Str2Uint(sc.TotalVotes)
-			content.Options = append(content.Options, &option)
-		case 8:
-			heading.Id = sc.Id
-			heading.Heading = sc.Heading
-			heading.SubHeading = sc.SubHeading
-			heading.Image = sc.Image
-			content.Heading = &heading
-		case 9:
-			paragraph.Id = sc.Id
-			paragraph.Heading = sc.Heading
-			paragraph.Text = sc.SubHeading
-			paragraph.Image = sc.Image
-			content.Paragraph = &paragraph
-		default:
+		current, ok := slidesById[slide.Id]
+		if !ok {
+			slide.Content = &content
+			current = &slide
+			slidesById[slide.Id] = current
+			slides = append(slides, current)
 		}
 
-		slide.Content = &content
-		if len(slides) == 0 {
-			slides = append(slides, &slide)
-		} else if slides[index].Id == slide.Id {
-			slides[index].Content.Options = append(slides[index].Content.Options, &option)
+		if !sc.Id.Valid {
+			continue
 		}
+
+		current.Content.SubContents = append(current.Content.SubContents, &model.SubContent{
+			Id:         uint(sc.Id.Int64),
+			Heading:    sc.Heading.String,
+			SubHeading: sc.SubHeading.String,
+			Image:      sc.Image.String,
+			TotalVotes: utils.Str2Uint(sc.TotalVotes.String),
+			IsCorrect:  sc.IsCorrect.Bool,
+		})
 	}
 
-	return slides[0], nil
+	return slides, rows.Err()
+}
+
+// encodeItems stores the items of a bullets slide as a JSON array
+func encodeItems(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
 }
 
 func (db *slideRepo) InsertSlide(slide *model.Slide) error {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	_, err := db.conn.ExecContext(ctx, stmtInsertSlide, slide.Id, slide.PresentationId, slide.Type)
+	_, err := db.conn.ExecContext(ctx, stmtInsertSlide, slide.Id, slide.PresentationId, slide.Type, slide.Position)
 	if err != nil {
 		return err
 	}
@@ -198,11 +242,59 @@ func (db *slideRepo) InsertSlide(slide *model.Slide) error {
 	return nil
 }
 
+// LockPresentation locks a presentation until the end of the transaction, so
+// that its slides are numbered one insertion at a time
+func (db *slideRepo) LockPresentation(presId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var id uint
+	return db.conn.QueryRowContext(ctx, stmtLockPresentation, presId).Scan(&id)
+}
+
+// FindSlideIds lists the ids of the slides of a presentation in their order
+func (db *slideRepo) FindSlideIds(presId string) ([]uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectSlideIds, presId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// UpdateSlidePositions numbers the slides of a presentation in the given
+// order, from 0
+func (db *slideRepo) UpdateSlidePositions(presId string, slideIds []uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	for position, id := range slideIds {
+		if _, err := db.conn.ExecContext(ctx, stmtUpdateSlidePosition, position, presId, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (db *slideRepo) InsertContent(slideId string, content *model.Content) error {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	_, err := db.conn.ExecContext(ctx, stmtInsertContent, content.Id, slideId, content.Title, content.Meta)
+	_, err := db.conn.ExecContext(ctx, stmtInsertContent, content.Id, slideId, content.Title, content.Meta, content.Image, content.AllowVoteChange, content.TimeLimit)
 	if err != nil {
 		return err
 	}
@@ -215,7 +307,7 @@ func (db *slideRepo) InsertOption(contentId string, options []*model.Option) err
 	defer cancel()
 
 	for _, option := range options {
-		_, err := db.conn.ExecContext(ctx, stmtInsertOption, option.Name, option.Image, contentId)
+		_, err := db.conn.ExecContext(ctx, stmtInsertOption, option.Name, option.Image, option.IsCorrect, contentId)
 		if err != nil {
 			return err
 		}
@@ -248,6 +340,90 @@ func (db *slideRepo) InsertParagraph(contentId string, paragraph *model.Paragrap
 	return nil
 }
 
+func (db *slideRepo) InsertBullets(contentId string, bullets *model.Bullets) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertBullets, bullets.Heading, encodeItems(bullets.Items), bullets.Image, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) InsertImage(contentId string, image *model.Image) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertImage, image.Heading, image.Url, image.Caption, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) InsertVideo(contentId string, video *model.Video) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertVideo, video.Heading, video.Url, video.StartTime, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) InsertBig(contentId string, big *model.Big) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertBig, big.Text, big.SubText, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) InsertQuote(contentId string, quote *model.Quote) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertQuote, quote.Quote, quote.Author, quote.Image, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) InsertNumber(contentId string, number *model.Number) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertNumber, number.Number, number.Caption, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) InsertInstructions(contentId string, instructions *model.Instructions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertInstructions, instructions.Heading, instructions.Text, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *slideRepo) UpdateSlide(presId string, slide model.Slide) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
@@ -264,7 +440,7 @@ func (db *slideRepo) UpdateContent(slideId string, content model.Content) (int64
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	res, err := db.conn.ExecContext(ctx, stmtUpdateContent, content.Title, content.Meta, slideId)
+	res, err := db.conn.ExecContext(ctx, stmtUpdateContent, content.Title, content.Meta, content.Image, content.AllowVoteChange, content.TimeLimit, slideId)
 	if err != nil {
 		return -1, err
 	}
@@ -277,7 +453,7 @@ func (db *slideRepo) UpdateOptions(contentId string, options []*model.Option) (i
 	defer cancel()
 
 	for _, option := range options {
-		_, err := db.conn.ExecContext(ctx, stmtUpdateOption, option.Name, option.Image, contentId, option.Id)
+		_, err := db.conn.ExecContext(ctx, stmtUpdateOption, option.Name, option.Image, option.IsCorrect, contentId, option.Id)
 		if err != nil {
 			return -1, err
 		}
@@ -286,11 +462,69 @@ func (db *slideRepo) UpdateOptions(contentId string, options []*model.Option) (i
 	return 0, nil
 }
 
-func (db *slideRepo) UpdateOptionVote(contentId string, optionId string) (int64, error) {
+// FindVoteChangePolicy tells whether the votes on an option of a content may
+// be changed, no rows when the content is not of the given slide type
+func (db *slideRepo) FindVoteChangePolicy(slideType uint, contentId, optionId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var allowChange bool
+	err := db.conn.QueryRowContext(ctx, stmtSelectVoteChangePolicy, optionId, contentId, slideType).Scan(&allowChange)
+	if err != nil {
+		return false, err
+	}
+
+	return allowChange, nil
+}
+
+func (db *slideRepo) FindVote(sessionId, contentId, participantId string) (*model.Vote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var vote model.Vote
+	err := db.conn.QueryRowContext(ctx, stmtSelectVote, sessionId, contentId, participantId).Scan(
+		&vote.Id,
+		&vote.SessionId,
+		&vote.ContentId,
+		&vote.OptionId,
+		&vote.ParticipantId,
+		&vote.CreatedAt,
+		&vote.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vote, nil
+}
+
+func (db *slideRepo) InsertVote(vote *model.Vote) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	vote.CreatedAt = time.Now()
+	vote.UpdatedAt = vote.CreatedAt
+	res, err := db.conn.ExecContext(ctx, stmtInsertVote,
+		vote.SessionId,
+		vote.ContentId,
+		vote.OptionId,
+		vote.ParticipantId,
+		vote.CreatedAt,
+		vote.UpdatedAt,
+	)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateVote(vote *model.Vote) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	res, err := db.conn.ExecContext(ctx, stmtUpdateOptionVote, optionId, contentId)
+	vote.UpdatedAt = time.Now()
+	res, err := db.conn.ExecContext(ctx, stmtUpdateVote, vote.OptionId, vote.UpdatedAt, vote.SessionId, vote.ContentId, vote.ParticipantId)
 	if err != nil {
 		return -1, err
 	}
@@ -322,6 +556,708 @@ func (db *slideRepo) UpdateParagraph(contentId string, paragraph *model.Paragrap
 	return res.RowsAffected()
 }
 
+func (db *slideRepo) UpdateBullets(contentId string, bullets *model.Bullets) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateBullets, bullets.Heading, encodeItems(bullets.Items), bullets.Image, contentId, bullets.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateImage(contentId string, image *model.Image) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateImage, image.Heading, image.Url, image.Caption, contentId, image.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateVideo(contentId string, video *model.Video) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateVideo, video.Heading, video.Url, video.StartTime, contentId, video.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateBig(contentId string, big *model.Big) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateBig, big.Text, big.SubText, contentId, big.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateQuote(contentId string, quote *model.Quote) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateQuote, quote.Quote, quote.Author, quote.Image, contentId, quote.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateNumber(contentId string, number *model.Number) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateNumber, number.Number, number.Caption, contentId, number.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) UpdateInstructions(contentId string, instructions *model.Instructions) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateInstructions, instructions.Heading, instructions.Text, contentId, instructions.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) InsertWord(word *model.WordCloudWord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	word.CreatedAt = time.Now()
+	_, err := db.conn.ExecContext(ctx, stmtInsertWord, word.SessionId, word.ContentId, word.ParticipantId, word.Word, word.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LockContent locks a content until the end of the transaction, so that the
+// responses to it are checked and stored one participant at a time
+func (db *slideRepo) LockContent(contentId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var id uint
+	return db.conn.QueryRowContext(ctx, stmtLockContent, contentId).Scan(&id)
+}
+
+func (db *slideRepo) CountParticipantWords(sessionId, contentId, participantId string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count int
+	err := db.conn.QueryRowContext(ctx, stmtCountParticipantWords, sessionId, contentId, participantId).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+func (db *slideRepo) FindWordFrequencies(sessionId, contentId string) ([]*model.WordFrequency, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectWordFrequencies, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var frequencies []*model.WordFrequency
+	for rows.Next() {
+		var frequency model.WordFrequency
+		if err = rows.Scan(&frequency.Word, &frequency.Count); err != nil {
+			return nil, err
+		}
+		frequencies = append(frequencies, &frequency)
+	}
+
+	return frequencies, rows.Err()
+}
+
+func (db *slideRepo) InsertAnswer(answer *model.OpenEndedAnswer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	answer.CreatedAt = time.Now()
+	res, err := db.conn.ExecContext(ctx, stmtInsertAnswer,
+		answer.SessionId,
+		answer.ContentId,
+		answer.ParticipantId,
+		answer.Author,
+		answer.Text,
+		answer.IsHidden,
+		answer.IsHighlighted,
+		answer.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	answer.Id = uint(id)
+
+	return nil
+}
+
+func (db *slideRepo) FindAnswers(sessionId, contentId string) ([]*model.OpenEndedAnswer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectAnswers, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []*model.OpenEndedAnswer
+	for rows.Next() {
+		var answer model.OpenEndedAnswer
+		if err = rows.Scan(
+			&answer.Id,
+			&answer.ContentId,
+			&answer.ParticipantId,
+			&answer.Author,
+			&answer.Text,
+			&answer.IsHidden,
+			&answer.IsHighlighted,
+			&answer.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		answers = append(answers, &answer)
+	}
+
+	return answers, rows.Err()
+}
+
+func (db *slideRepo) FindAnswerById(contentId, answerId string) (*model.OpenEndedAnswer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var answer model.OpenEndedAnswer
+	err := db.conn.QueryRowContext(ctx, stmtSelectAnswerById, contentId, answerId).Scan(
+		&answer.Id,
+		&answer.ContentId,
+		&answer.ParticipantId,
+		&answer.Author,
+		&answer.Text,
+		&answer.IsHidden,
+		&answer.IsHighlighted,
+		&answer.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &answer, nil
+}
+
+func (db *slideRepo) UpdateAnswerModeration(answer *model.OpenEndedAnswer) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateAnswerModeration, answer.IsHidden, answer.IsHighlighted, answer.ContentId, answer.Id)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (db *slideRepo) InsertScale(contentId string, scale *model.Scale) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtInsertScale, scale.MinValue, scale.MaxValue, scale.MinLabel, scale.MaxLabel, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindScale(contentId string) (*model.Scale, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var scale model.Scale
+	err := db.conn.QueryRowContext(ctx, stmtSelectScale, contentId).Scan(
+		&scale.Id,
+		&scale.MinValue,
+		&scale.MaxValue,
+		&scale.MinLabel,
+		&scale.MaxLabel,
+		&scale.ContentId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scale, nil
+}
+
+func (db *slideRepo) UpdateScale(contentId string, scale *model.Scale) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	res, err := db.conn.ExecContext(ctx, stmtUpdateScale, scale.MinValue, scale.MaxValue, scale.MinLabel, scale.MaxLabel, contentId)
+	if err != nil {
+		return -1, err
+	}
+
+	return res.RowsAffected()
+}
+
+// UpsertAnswerPolicy stores how the typed answers of a type answer slide are
+// matched against its accepted answers
+func (db *slideRepo) UpsertAnswerPolicy(contentId string, policy *model.AnswerPolicy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtUpsertAnswerPolicy, policy.Policy, policy.MaxDistance, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindAnswerPolicy(contentId string) (*model.AnswerPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var policy model.AnswerPolicy
+	err := db.conn.QueryRowContext(ctx, stmtSelectAnswerPolicy, contentId).Scan(
+		&policy.Id,
+		&policy.Policy,
+		&policy.MaxDistance,
+		&policy.ContentId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func (db *slideRepo) FindOptionIds(contentId string) ([]uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectOptionIds, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (db *slideRepo) HasRatings(sessionId, contentId, participantId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count int
+	err := db.conn.QueryRowContext(ctx, stmtCountParticipantRatings, sessionId, contentId, participantId).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (db *slideRepo) UpsertRatings(sessionId, contentId, participantId string, ratings []*model.Rating) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	now := time.Now()
+	for _, rating := range ratings {
+		_, err := db.conn.ExecContext(ctx, stmtUpsertRating, sessionId, contentId, rating.OptionId, participantId, rating.Value, now, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindRatingCounts(sessionId, contentId string) ([]*model.RatingCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectRatingCounts, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*model.RatingCount
+	for rows.Next() {
+		var count model.RatingCount
+		if err = rows.Scan(&count.OptionId, &count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+
+	return counts, rows.Err()
+}
+
+func (db *slideRepo) FindAllowVoteChange(contentId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var allowChange bool
+	err := db.conn.QueryRowContext(ctx, stmtSelectAllowVoteChange, contentId).Scan(&allowChange)
+	if err != nil {
+		return false, err
+	}
+
+	return allowChange, nil
+}
+
+func (db *slideRepo) HasRankingBallot(sessionId, contentId, participantId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count int
+	err := db.conn.QueryRowContext(ctx, stmtCountParticipantPositions, sessionId, contentId, participantId).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (db *slideRepo) ReplaceRankingBallot(sessionId, contentId, participantId string, ranking []uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtDeleteParticipantPositions, sessionId, contentId, participantId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for position, optionId := range ranking {
+		_, err = db.conn.ExecContext(ctx, stmtInsertPosition, sessionId, contentId, optionId, participantId, position, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindPositionCounts(sessionId, contentId string) ([]*model.PositionCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectPositionCounts, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*model.PositionCount
+	for rows.Next() {
+		var count model.PositionCount
+		if err = rows.Scan(&count.OptionId, &count.Position, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+
+	return counts, rows.Err()
+}
+
+func (db *slideRepo) CountBallots(sessionId, contentId string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count uint
+	err := db.conn.QueryRowContext(ctx, stmtCountBallots, sessionId, contentId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (db *slideRepo) HasAllocation(sessionId, contentId, participantId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count int
+	err := db.conn.QueryRowContext(ctx, stmtCountParticipantAllocations, sessionId, contentId, participantId).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (db *slideRepo) ReplaceAllocation(sessionId, contentId, participantId string, allocations []*model.Allocation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtDeleteParticipantAllocations, sessionId, contentId, participantId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, allocation := range allocations {
+		_, err = db.conn.ExecContext(ctx, stmtInsertAllocation, sessionId, contentId, allocation.OptionId, participantId, allocation.Points, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindPointTotals(sessionId, contentId string) ([]*model.OptionPoints, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectPointTotals, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.OptionPoints
+	for rows.Next() {
+		var total model.OptionPoints
+		if err = rows.Scan(&total.OptionId, &total.Total); err != nil {
+			return nil, err
+		}
+		totals = append(totals, &total)
+	}
+
+	return totals, rows.Err()
+}
+
+func (db *slideRepo) CountAllocationBallots(sessionId, contentId string) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count uint
+	err := db.conn.QueryRowContext(ctx, stmtCountAllocationBallots, sessionId, contentId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (db *slideRepo) UpsertGridAxes(contentId string, axes *model.GridAxes) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtUpsertGridAxes,
+		axes.XLabel,
+		axes.XMin,
+		axes.XMax,
+		axes.YLabel,
+		axes.YMin,
+		axes.YMax,
+		contentId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindGridAxes(contentId string) (*model.GridAxes, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var axes model.GridAxes
+	err := db.conn.QueryRowContext(ctx, stmtSelectGridAxes, contentId).Scan(
+		&axes.Id,
+		&axes.XLabel,
+		&axes.XMin,
+		&axes.XMax,
+		&axes.YLabel,
+		&axes.YMin,
+		&axes.YMax,
+		&axes.ContentId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &axes, nil
+}
+
+func (db *slideRepo) HasGridPoints(sessionId, contentId, participantId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var count int
+	err := db.conn.QueryRowContext(ctx, stmtCountParticipantGridPoints, sessionId, contentId, participantId).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (db *slideRepo) UpsertGridPoints(sessionId, contentId, participantId string, points []*model.GridPoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	now := time.Now()
+	for _, point := range points {
+		_, err := db.conn.ExecContext(ctx, stmtUpsertGridPoint, sessionId, contentId, point.OptionId, participantId, point.X, point.Y, now, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindGridPoints(sessionId, contentId string) ([]*model.GridPoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectGridPoints, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []*model.GridPoint
+	for rows.Next() {
+		var point model.GridPoint
+		if err = rows.Scan(&point.OptionId, &point.X, &point.Y); err != nil {
+			return nil, err
+		}
+		points = append(points, &point)
+	}
+
+	return points, rows.Err()
+}
+
+func (db *slideRepo) UpsertPin(sessionId, contentId, participantId string, pin *model.Pin) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	now := time.Now()
+	_, err := db.conn.ExecContext(ctx, stmtUpsertPin, sessionId, contentId, participantId, pin.X, pin.Y, now, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindPins(sessionId, contentId string) ([]*model.Pin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := db.conn.QueryContext(ctx, stmtSelectPins, sessionId, contentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pins []*model.Pin
+	for rows.Next() {
+		var pin model.Pin
+		if err = rows.Scan(&pin.X, &pin.Y); err != nil {
+			return nil, err
+		}
+		pins = append(pins, &pin)
+	}
+
+	return pins, rows.Err()
+}
+
+// ResolvePrediction stores the winner of a who will win slide for a session
+// and scores the prediction of every participant of the session, replacing
+// the scores of a previous resolution
+func (db *slideRepo) ResolvePrediction(sessionId, contentId, optionId string, points int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := db.conn.ExecContext(ctx, stmtUpsertPredictionResolution, sessionId, contentId, optionId, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.ExecContext(ctx, stmtDeletePredictionScores, sessionId, contentId)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.ExecContext(ctx, stmtInsertPredictionScores, optionId, optionId, points, sessionId, contentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *slideRepo) FindPredictionResult(sessionId, contentId string) (*model.PredictionResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var result model.PredictionResult
+	err := db.conn.QueryRowContext(ctx, stmtSelectPredictionResult, sessionId, contentId).Scan(
+		&result.WinnerOptionId,
+		&result.ResolvedAt,
+		&result.Predictions,
+		&result.Correct,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 func (db *slideRepo) DeleteSlide(presId, slideId string) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// conn is what the repositories run their statements on, either the
+// connection pool or the transaction of a unit of work
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Tx holds the repositories taking part in a unit of work, all running their
+// statements in its transaction
+type Tx struct {
+	Slide    ISlideRepo
+	Pres     IPresRepo
+	Session  ISessionRepo
+	Question IQuestionRepo
+}
+
+// IUnitOfWork runs a group of writes in one transaction, so that they are
+// either all stored or none of them is
+type IUnitOfWork interface {
+	Do(fn func(tx *Tx) error) error
+}
+
+type unitOfWork struct {
+	conn *sql.DB
+}
+
+func NewUnitOfWork(sqldb *sql.DB) *unitOfWork {
+	return &unitOfWork{
+		conn: sqldb,
+	}
+}
+
+// Do commits the transaction when fn succeeds and rolls it back when fn
+// returns an error or panics. The whole unit of work is bound by dbTimeout
+func (uow *unitOfWork) Do(fn func(tx *Tx) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	sqlTx, err := uow.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = sqlTx.Rollback()
+		}
+	}()
+
+	tx := &Tx{
+		Slide:    &slideRepo{conn: sqlTx},
+		Pres:     &presRepo{conn: sqlTx},
+		Session:  &sessionRepo{conn: sqlTx},
+		Question: &questionRepo{conn: sqlTx},
+	}
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = sqlTx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
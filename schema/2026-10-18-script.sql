@@ -0,0 +1,648 @@
+-- VOTE LEDGER --
+
+ALTER TABLE `contents`
+    ADD `allow_vote_change` BOOLEAN DEFAULT FALSE;
+
+CREATE TABLE `option_votes`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `option_id`      BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `created_at`     DATETIME,
+    `updated_at`     DATETIME,
+    UNIQUE KEY `option_votes_content_participant_uk` (`content_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `option_votes`
+    ADD (
+        CONSTRAINT `option_votes_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `option_votes_options_id_fk` FOREIGN KEY (`option_id`) REFERENCES `options` (`id`)
+            ON DELETE CASCADE
+        );
+
+-- The totals counted before the ledger are kept as one anonymous vote each
+SET SESSION cte_max_recursion_depth = 1000000;
+
+INSERT INTO `option_votes` (`content_id`, `option_id`, `participant_id`)
+WITH RECURSIVE `legacy_votes` (`content_id`, `option_id`, `n`) AS
+(
+    SELECT o.content_id, o.id, 1
+    FROM `options` o
+    WHERE o.content_id IS NOT NULL AND o.total_votes > 0
+    UNION ALL
+    SELECT v.content_id, v.option_id, v.n + 1
+    FROM `legacy_votes` v
+    JOIN `options` o ON o.id = v.option_id
+    WHERE v.n < o.total_votes
+)
+SELECT content_id, option_id, CONCAT('legacy:', option_id, ':', n)
+FROM `legacy_votes`;
+
+-- SLIDE TYPES --
+
+-- The seeded slide types are renumbered so that Q&A, which is not a slide,
+-- comes after the slide types, matching the ids of model.SlideType*. The ids
+-- are moved out of the way first since they overlap. Slides are left as they
+-- are: they were only ever stored as multiple choice (1), heading (8) and
+-- paragraph (9), which already are the ids of these types
+SET FOREIGN_KEY_CHECKS = 0;
+
+UPDATE `question_types`
+SET id = id + 100
+WHERE id >= 6;
+
+UPDATE `question_types`
+SET id = IF(id = 106, 21, id - 101)
+WHERE id >= 106;
+
+ALTER TABLE `question_types`
+    AUTO_INCREMENT = 22;
+
+SET FOREIGN_KEY_CHECKS = 1;
+
+-- WORD CLOUD --
+
+CREATE TABLE `word_cloud_words`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `word`           VARCHAR(25)  NOT NULL,
+    `created_at`     DATETIME,
+    INDEX `word_cloud_words_content_word_idx` (`content_id`, `word`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `word_cloud_words`
+    ADD CONSTRAINT `word_cloud_words_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+-- OPEN ENDED --
+
+CREATE TABLE `open_ended_answers`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `author`         VARCHAR(250) DEFAULT '',
+    `text`           VARCHAR(500) NOT NULL,
+    `is_hidden`      BOOLEAN      DEFAULT FALSE,
+    `is_highlighted` BOOLEAN      DEFAULT FALSE,
+    `created_at`     DATETIME
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `open_ended_answers`
+    ADD CONSTRAINT `open_ended_answers_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+-- SCALES --
+
+CREATE TABLE `scales`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `min_value`  INT          DEFAULT 1,
+    `max_value`  INT          DEFAULT 5,
+    `min_label`  VARCHAR(50)  DEFAULT '',
+    `max_label`  VARCHAR(50)  DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `scale_ratings`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `option_id`      BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `value`          INT          NOT NULL,
+    `created_at`     DATETIME,
+    `updated_at`     DATETIME,
+    UNIQUE KEY `scale_ratings_option_participant_uk` (`option_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `scales`
+    ADD CONSTRAINT `scales_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `scale_ratings`
+    ADD (
+        CONSTRAINT `scale_ratings_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `scale_ratings_options_id_fk` FOREIGN KEY (`option_id`) REFERENCES `options` (`id`)
+            ON DELETE CASCADE
+        );
+
+-- RANKING --
+
+CREATE TABLE `ranking_positions`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `option_id`      BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `position`       INT          NOT NULL,
+    `created_at`     DATETIME,
+    UNIQUE KEY `ranking_positions_option_participant_uk` (`option_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `ranking_positions`
+    ADD (
+        CONSTRAINT `ranking_positions_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `ranking_positions_options_id_fk` FOREIGN KEY (`option_id`) REFERENCES `options` (`id`)
+            ON DELETE CASCADE
+        );
+
+-- Q&A --
+
+ALTER TABLE `questions`
+    ADD `participant_id` VARCHAR(100) DEFAULT '';
+
+ALTER TABLE `question_user`
+    ADD (
+        `participant_id` VARCHAR(100) NOT NULL DEFAULT '',
+        UNIQUE KEY `question_user_question_participant_uk` (`question_id`, `participant_id`)
+        );
+
+ALTER TABLE `questions`
+    ADD CONSTRAINT `questions_presentations_id_fk` FOREIGN KEY (`present_id`) REFERENCES `presentations` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `question_user`
+    ADD CONSTRAINT `question_user_questions_id_fk` FOREIGN KEY (`question_id`) REFERENCES `questions` (`id`)
+        ON DELETE CASCADE;
+
+-- CHAT --
+
+ALTER TABLE `messages`
+    ADD (
+        `created_at` DATETIME,
+        KEY `messages_present_id_id_idx` (`present_id`, `id`)
+        );
+
+ALTER TABLE `messages`
+    ADD CONSTRAINT `messages_presentations_id_fk` FOREIGN KEY (`present_id`) REFERENCES `presentations` (`id`)
+        ON DELETE CASCADE;
+
+-- QUIZ --
+
+ALTER TABLE `contents`
+    ADD `time_limit` INT DEFAULT 0;
+
+ALTER TABLE `options`
+    ADD `is_correct` BOOLEAN DEFAULT FALSE;
+
+CREATE TABLE `quiz_responses`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `username`       VARCHAR(250) DEFAULT '',
+    `option_id`      BIGINT,
+    `answer`         VARCHAR(250) DEFAULT '',
+    `is_correct`     BOOLEAN      DEFAULT FALSE,
+    `points`         INT          DEFAULT 0,
+    `response_ms`    INT          DEFAULT 0,
+    `created_at`     DATETIME,
+    UNIQUE KEY `quiz_responses_content_participant_uk` (`content_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `quiz_responses`
+    ADD CONSTRAINT `quiz_responses_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+-- TYPE ANSWER --
+
+CREATE TABLE `answer_policies`
+(
+    `id`           BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `policy`       VARCHAR(20) DEFAULT 'normalized',
+    `max_distance` INT         DEFAULT 0,
+    `content_id`   BIGINT,
+    UNIQUE KEY `answer_policies_content_id_uk` (`content_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `answer_policies`
+    ADD CONSTRAINT `answer_policies_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+-- 100 POINTS --
+
+CREATE TABLE `point_allocations`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `option_id`      BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `points`         INT          NOT NULL,
+    `created_at`     DATETIME,
+    UNIQUE KEY `point_allocations_option_participant_uk` (`option_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `point_allocations`
+    ADD (
+        CONSTRAINT `point_allocations_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `point_allocations_options_id_fk` FOREIGN KEY (`option_id`) REFERENCES `options` (`id`)
+            ON DELETE CASCADE
+        );
+
+-- 2X2 GRID --
+
+CREATE TABLE `grid_axes`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `x_label`    VARCHAR(50) DEFAULT '',
+    `x_min`      INT         DEFAULT 0,
+    `x_max`      INT         DEFAULT 10,
+    `y_label`    VARCHAR(50) DEFAULT '',
+    `y_min`      INT         DEFAULT 0,
+    `y_max`      INT         DEFAULT 10,
+    `content_id` BIGINT,
+    UNIQUE KEY `grid_axes_content_id_uk` (`content_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `grid_points`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `option_id`      BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `x`              INT          NOT NULL,
+    `y`              INT          NOT NULL,
+    `created_at`     DATETIME,
+    `updated_at`     DATETIME,
+    UNIQUE KEY `grid_points_option_participant_uk` (`option_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `grid_axes`
+    ADD CONSTRAINT `grid_axes_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `grid_points`
+    ADD (
+        CONSTRAINT `grid_points_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `grid_points_options_id_fk` FOREIGN KEY (`option_id`) REFERENCES `options` (`id`)
+            ON DELETE CASCADE
+        );
+
+-- PIN ON IMAGE --
+
+ALTER TABLE `contents`
+    ADD `image` VARCHAR(1000) DEFAULT '';
+
+CREATE TABLE `image_pins`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `x`              DOUBLE       NOT NULL,
+    `y`              DOUBLE       NOT NULL,
+    `created_at`     DATETIME,
+    `updated_at`     DATETIME,
+    UNIQUE KEY `image_pins_content_participant_uk` (`content_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `image_pins`
+    ADD CONSTRAINT `image_pins_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+-- WHO WILL WIN --
+
+CREATE TABLE `prediction_resolutions`
+(
+    `id`          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`  BIGINT NOT NULL,
+    `option_id`   BIGINT NOT NULL,
+    `resolved_at` DATETIME,
+    UNIQUE KEY `prediction_resolutions_content_id_uk` (`content_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `prediction_scores`
+(
+    `id`             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `content_id`     BIGINT       NOT NULL,
+    `participant_id` VARCHAR(100) NOT NULL,
+    `option_id`      BIGINT       NOT NULL,
+    `is_correct`     BOOLEAN      DEFAULT FALSE,
+    `points`         INT          DEFAULT 0,
+    UNIQUE KEY `prediction_scores_content_participant_uk` (`content_id`, `participant_id`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `prediction_resolutions`
+    ADD (
+        CONSTRAINT `prediction_resolutions_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `prediction_resolutions_options_id_fk` FOREIGN KEY (`option_id`) REFERENCES `options` (`id`)
+            ON DELETE CASCADE
+        );
+
+ALTER TABLE `prediction_scores`
+    ADD CONSTRAINT `prediction_scores_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE CASCADE;
+
+-- CONTENT SLIDES --
+
+CREATE TABLE `bullets`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `heading`    VARCHAR(150) DEFAULT '',
+    `items`      TEXT,
+    `image`      VARCHAR(250) DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `images`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `heading`    VARCHAR(150) DEFAULT '',
+    `url`        VARCHAR(1000) DEFAULT '',
+    `caption`    VARCHAR(500) DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `videos`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `heading`    VARCHAR(150) DEFAULT '',
+    `url`        VARCHAR(1000) DEFAULT '',
+    `start_time` INT DEFAULT 0,
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `big_texts`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `text`       VARCHAR(150) DEFAULT '',
+    `sub_text`   VARCHAR(500) DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `quotes`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `quote`      VARCHAR(800) DEFAULT '',
+    `author`     VARCHAR(150) DEFAULT '',
+    `image`      VARCHAR(250) DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `numbers`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `number`     VARCHAR(50) DEFAULT '',
+    `caption`    VARCHAR(500) DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE `instructions`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `heading`    VARCHAR(150) DEFAULT '',
+    `text`       VARCHAR(800) DEFAULT '',
+    `content_id` BIGINT
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `bullets`
+    ADD CONSTRAINT `bullets_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `images`
+    ADD CONSTRAINT `images_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `videos`
+    ADD CONSTRAINT `videos_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `big_texts`
+    ADD CONSTRAINT `big_texts_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `quotes`
+    ADD CONSTRAINT `quotes_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `numbers`
+    ADD CONSTRAINT `numbers_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+ALTER TABLE `instructions`
+    ADD CONSTRAINT `instructions_contents_id_fk` FOREIGN KEY (`content_id`) REFERENCES `contents` (`id`)
+        ON DELETE SET NULL;
+
+-- SLIDE ORDER --
+
+ALTER TABLE `slides`
+    ADD `position` INT NOT NULL DEFAULT 0;
+
+-- Existing slides are numbered in id order within their presentation
+UPDATE `slides` s
+    JOIN (SELECT id, ROW_NUMBER() OVER (PARTITION BY pres_id ORDER BY id) - 1 AS position FROM `slides`) o ON s.id = o.id
+SET s.position = o.position;
+
+CREATE INDEX `slides_pres_id_position_idx` ON `slides` (`pres_id`, `position`);
+
+-- SESSIONS --
+
+CREATE TABLE `presentation_sessions`
+(
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `pres_id`    BIGINT NOT NULL,
+    `group_id`   BIGINT,
+    `started_at` DATETIME,
+    `ended_at`   DATETIME,
+    INDEX `presentation_sessions_pres_id_started_at_idx` (`pres_id`, `started_at`)
+) DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE `presentation_sessions`
+    ADD (
+        CONSTRAINT `presentation_sessions_presentations_id_fk` FOREIGN KEY (`pres_id`) REFERENCES `presentations` (`id`)
+            ON DELETE CASCADE,
+        CONSTRAINT `presentation_sessions_groups_id_fk` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`)
+            ON DELETE SET NULL
+        );
+
+ALTER TABLE `option_votes`
+    ADD `session_id` BIGINT,
+    DROP INDEX `option_votes_content_participant_uk`,
+    ADD UNIQUE KEY `option_votes_content_session_participant_uk` (`content_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `word_cloud_words`
+    ADD `session_id` BIGINT,
+    DROP INDEX `word_cloud_words_content_word_idx`,
+    ADD INDEX `word_cloud_words_content_session_word_idx` (`content_id`, `session_id`, `word`);
+
+ALTER TABLE `open_ended_answers`
+    ADD `session_id` BIGINT,
+    ADD INDEX `open_ended_answers_content_session_idx` (`content_id`, `session_id`);
+
+ALTER TABLE `scale_ratings`
+    ADD `session_id` BIGINT,
+    DROP INDEX `scale_ratings_option_participant_uk`,
+    ADD UNIQUE KEY `scale_ratings_option_session_participant_uk` (`option_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `ranking_positions`
+    ADD `session_id` BIGINT,
+    DROP INDEX `ranking_positions_option_participant_uk`,
+    ADD UNIQUE KEY `ranking_positions_option_session_participant_uk` (`option_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `quiz_responses`
+    ADD `session_id` BIGINT,
+    DROP INDEX `quiz_responses_content_participant_uk`,
+    ADD UNIQUE KEY `quiz_responses_content_session_participant_uk` (`content_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `point_allocations`
+    ADD `session_id` BIGINT,
+    DROP INDEX `point_allocations_option_participant_uk`,
+    ADD UNIQUE KEY `point_allocations_option_session_participant_uk` (`option_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `grid_points`
+    ADD `session_id` BIGINT,
+    DROP INDEX `grid_points_option_participant_uk`,
+    ADD UNIQUE KEY `grid_points_option_session_participant_uk` (`option_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `image_pins`
+    ADD `session_id` BIGINT,
+    DROP INDEX `image_pins_content_participant_uk`,
+    ADD UNIQUE KEY `image_pins_content_session_participant_uk` (`content_id`, `session_id`, `participant_id`);
+
+ALTER TABLE `prediction_resolutions`
+    ADD `session_id` BIGINT,
+    DROP INDEX `prediction_resolutions_content_id_uk`,
+    ADD UNIQUE KEY `prediction_resolutions_content_session_uk` (`content_id`, `session_id`);
+
+ALTER TABLE `prediction_scores`
+    ADD `session_id` BIGINT,
+    DROP INDEX `prediction_scores_content_participant_uk`,
+    ADD UNIQUE KEY `prediction_scores_content_session_participant_uk` (`content_id`, `session_id`, `participant_id`);
+
+-- Responses collected before sessions existed are kept in one ended session
+-- per presentation
+INSERT INTO `presentation_sessions` (pres_id, started_at, ended_at)
+SELECT p.id, p.created_at, COALESCE(p.modified_at, p.created_at)
+FROM `presentations` p
+WHERE EXISTS (SELECT 1
+              FROM `slides` s
+                       JOIN `contents` c ON c.slide_id = s.id
+              WHERE s.pres_id = p.id
+                AND c.id IN (SELECT content_id FROM `option_votes`
+                             UNION SELECT content_id FROM `word_cloud_words`
+                             UNION SELECT content_id FROM `open_ended_answers`
+                             UNION SELECT content_id FROM `scale_ratings`
+                             UNION SELECT content_id FROM `ranking_positions`
+                             UNION SELECT content_id FROM `quiz_responses`
+                             UNION SELECT content_id FROM `point_allocations`
+                             UNION SELECT content_id FROM `grid_points`
+                             UNION SELECT content_id FROM `image_pins`));
+
+UPDATE `option_votes` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `word_cloud_words` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `open_ended_answers` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `scale_ratings` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `ranking_positions` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `quiz_responses` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `point_allocations` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `grid_points` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `image_pins` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `prediction_resolutions` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+UPDATE `prediction_scores` t
+    JOIN `contents` c ON t.content_id = c.id
+    JOIN `slides` s ON c.slide_id = s.id
+    JOIN `presentation_sessions` ps ON ps.pres_id = s.pres_id
+SET t.session_id = ps.id;
+
+ALTER TABLE `option_votes`
+    ADD CONSTRAINT `option_votes_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `word_cloud_words`
+    ADD CONSTRAINT `word_cloud_words_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `open_ended_answers`
+    ADD CONSTRAINT `open_ended_answers_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `scale_ratings`
+    ADD CONSTRAINT `scale_ratings_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `ranking_positions`
+    ADD CONSTRAINT `ranking_positions_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `quiz_responses`
+    ADD CONSTRAINT `quiz_responses_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `point_allocations`
+    ADD CONSTRAINT `point_allocations_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `grid_points`
+    ADD CONSTRAINT `grid_points_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `image_pins`
+    ADD CONSTRAINT `image_pins_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `prediction_resolutions`
+    ADD CONSTRAINT `prediction_resolutions_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
+
+ALTER TABLE `prediction_scores`
+    ADD CONSTRAINT `prediction_scores_presentation_sessions_id_fk` FOREIGN KEY (`session_id`) REFERENCES `presentation_sessions` (`id`)
+        ON DELETE CASCADE;
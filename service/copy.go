@@ -0,0 +1,178 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrSlideNotFound = errors.New("slide not found")
+	ErrPresNotFound  = errors.New("presentation not found")
+)
+
+// DuplicateSlide copies a slide of a presentation right after it, with fresh
+// ids and none of the audience responses
+func (svc *slideService) DuplicateSlide(presId, slideId string) (*model.Slide, error) {
+	slide, err := svc.GetSlideById(slideId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSlideNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if utils.Uint2Str(slide.PresentationId) != presId {
+		return nil, ErrSlideNotFound
+	}
+
+	duplicate := copySlide(slide, slide.PresentationId)
+	err = svc.uow.Do(func(tx *repository.Tx) error {
+		ids, err := tx.Slide.FindSlideIds(presId)
+		if err != nil {
+			return err
+		}
+		index := len(ids)
+		for i, id := range ids {
+			if id == slide.Id {
+				index = i + 1
+			}
+		}
+		return svc.within(tx).insertSlide(duplicate, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Read back so that the sub-contents carry their own ids
+	return svc.GetSlideById(utils.Uint2Str(duplicate.Id))
+}
+
+// CopyPresentation copies a presentation and all its slides, in their order,
+// into a new presentation owned by the user
+func (svc *slideService) CopyPresentation(presId, userId string) (*model.Pres, error) {
+	slides, err := svc.GetAllSlides(presId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pres *model.Pres
+	err = svc.uow.Do(func(tx *repository.Tx) error {
+		source, err := tx.Pres.FindPresentationById(presId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPresNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		pres = &model.Pres{
+			Id:   utils.Str2Uint(utils.GenerateRandomNumber(8)),
+			Name: copyName(source.Name),
+		}
+		if err = tx.Pres.InsertPresentation(pres, userId); err != nil {
+			return err
+		}
+
+		// The copy starts empty, so its slides take their positions as they go
+		txSvc := svc.within(tx)
+		for i, slide := range slides {
+			copied := copySlide(slide, pres.Id)
+			copied.Position = i
+			if err = txSvc.storeSlide(copied); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pres.Owner = &model.User{Id: utils.Str2Uint(userId)}
+	return pres, nil
+}
+
+// copyName names the copy of a presentation after it, cut to fit the name
+// column
+func copyName(name string) string {
+	copied := []rune("Copy of " + name)
+	if len(copied) > maxNameLength {
+		copied = copied[:maxNameLength]
+	}
+	return strings.TrimSpace(string(copied))
+}
+
+// copySlide is a loaded slide with fresh ids, moved to a presentation, with
+// its configuration but without its results and vote totals
+func copySlide(slide *model.Slide, presId uint) *model.Slide {
+	content := portableContent(slide.Content)
+	content.Id = utils.Str2Uint(utils.GenerateRandomNumber(8))
+
+	return &model.Slide{
+		Id:             utils.Str2Uint(utils.GenerateRandomNumber(8)),
+		PresentationId: presId,
+		Type:           slide.Type,
+		Content:        content,
+	}
+}
+
+// portableContent is the configuration of a loaded content, free of ids,
+// results and vote totals, which can be stored anew
+func portableContent(content *model.Content) *model.Content {
+	portable := &model.Content{
+		Title:           content.Title,
+		Meta:            content.Meta,
+		Image:           content.Image,
+		AllowVoteChange: content.AllowVoteChange,
+		TimeLimit:       content.TimeLimit,
+	}
+	for _, option := range content.Options {
+		portable.Options = append(portable.Options, &model.Option{
+			Name:      option.Name,
+			Image:     option.Image,
+			IsCorrect: option.IsCorrect,
+		})
+	}
+
+	if c := content.Heading; c != nil {
+		portable.Heading = &model.Heading{Heading: c.Heading, SubHeading: c.SubHeading, Image: c.Image}
+	}
+	if c := content.Paragraph; c != nil {
+		portable.Paragraph = &model.Paragraph{Heading: c.Heading, Text: c.Text, Image: c.Image}
+	}
+	if c := content.Scale; c != nil {
+		portable.Scale = &model.Scale{MinValue: c.MinValue, MaxValue: c.MaxValue, MinLabel: c.MinLabel, MaxLabel: c.MaxLabel}
+	}
+	if c := content.Bullets; c != nil {
+		portable.Bullets = &model.Bullets{Heading: c.Heading, Items: c.Items, Image: c.Image}
+	}
+	if c := content.ImageSlide; c != nil {
+		portable.ImageSlide = &model.Image{Heading: c.Heading, Url: c.Url, Caption: c.Caption}
+	}
+	if c := content.Video; c != nil {
+		portable.Video = &model.Video{Heading: c.Heading, Url: c.Url, StartTime: c.StartTime}
+	}
+	if c := content.Big; c != nil {
+		portable.Big = &model.Big{Text: c.Text, SubText: c.SubText}
+	}
+	if c := content.Quote; c != nil {
+		portable.Quote = &model.Quote{Quote: c.Quote, Author: c.Author, Image: c.Image}
+	}
+	if c := content.Number; c != nil {
+		portable.Number = &model.Number{Number: c.Number, Caption: c.Caption}
+	}
+	if c := content.Instructions; c != nil {
+		portable.Instructions = &model.Instructions{Heading: c.Heading, Text: c.Text}
+	}
+	if c := content.AnswerPolicy; c != nil {
+		portable.AnswerPolicy = &model.AnswerPolicy{Policy: c.Policy, MaxDistance: c.MaxDistance}
+	}
+	if c := content.GridAxes; c != nil {
+		portable.GridAxes = &model.GridAxes{XLabel: c.XLabel, XMin: c.XMin, XMax: c.XMax, YLabel: c.YLabel, YMin: c.YMin, YMax: c.YMax}
+	}
+
+	return portable
+}
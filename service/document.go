@@ -0,0 +1,277 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DocumentVersion is the version of the exported documents, the only one
+// imported
+const DocumentVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported document version")
+	ErrMissingName        = errors.New("name is required")
+	ErrMissingSlide       = errors.New("slide is empty")
+	ErrTextTooLong        = errors.New("text is too long")
+)
+
+// Longest texts the columns of a presentation hold, in characters
+const (
+	maxNameLength    = 50
+	maxTitleLength   = 150
+	maxMetaLength    = 80
+	maxLabelLength   = 50
+	maxCaptionLength = 500
+	maxTextLength    = 800
+	maxImageLength   = 250
+	maxUrlLength     = 1000
+)
+
+// DocumentError lists every problem found in an imported document
+type DocumentError struct {
+	Problems []*model.DocumentProblem
+}
+
+func (e *DocumentError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		problems = append(problems, problem.Path+": "+problem.Message)
+	}
+	return "invalid document: " + strings.Join(problems, "; ")
+}
+
+// ExportPresentation builds the document of a presentation, its slides in
+// order with their configuration but none of the audience responses
+func (svc *slideService) ExportPresentation(presId string) (*model.PresDocument, error) {
+	var doc *model.PresDocument
+	err := svc.uow.Do(func(tx *repository.Tx) error {
+		pres, err := tx.Pres.FindPresentationById(presId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPresNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		slides, err := svc.within(tx).GetAllSlides(presId)
+		if err != nil {
+			return err
+		}
+
+		doc = &model.PresDocument{
+			Version:    DocumentVersion,
+			ExportedAt: time.Now(),
+			Name:       pres.Name,
+			Slides:     make([]*model.DocumentSlide, 0, len(slides)),
+		}
+		for _, slide := range slides {
+			doc.Slides = append(doc.Slides, &model.DocumentSlide{
+				Type:    slide.Type,
+				Content: portableContent(slide.Content),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// ImportPresentation recreates the presentation of a document for the user.
+// The document is checked as a whole first, a *DocumentError listing all its
+// problems is returned when it is invalid
+func (svc *slideService) ImportPresentation(doc *model.PresDocument, userId string) (*model.Pres, error) {
+	if err := svc.validateDocument(doc); err != nil {
+		return nil, err
+	}
+
+	pres := &model.Pres{
+		Id:   utils.Str2Uint(utils.GenerateRandomNumber(8)),
+		Name: strings.TrimSpace(doc.Name),
+	}
+	err := svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Pres.InsertPresentation(pres, userId); err != nil {
+			return err
+		}
+
+		txSvc := svc.within(tx)
+		for i, slide := range doc.Slides {
+			content := portableContent(slide.Content)
+			content.Id = utils.Str2Uint(utils.GenerateRandomNumber(8))
+			err := txSvc.storeSlide(&model.Slide{
+				Id:             utils.Str2Uint(utils.GenerateRandomNumber(8)),
+				PresentationId: pres.Id,
+				Type:           slide.Type,
+				Position:       i,
+				Content:        content,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pres.Owner = &model.User{Id: utils.Str2Uint(userId)}
+	return pres, nil
+}
+
+func (svc *slideService) validateDocument(doc *model.PresDocument) error {
+	var problems []*model.DocumentProblem
+	report := func(path string, err error) {
+		problems = append(problems, &model.DocumentProblem{Path: path, Message: err.Error()})
+	}
+
+	if doc.Version != DocumentVersion {
+		report("version", ErrUnsupportedVersion)
+	}
+	if isBlank(doc.Name) {
+		report("name", ErrMissingName)
+	}
+	if utf8.RuneCountInString(doc.Name) > maxNameLength {
+		report("name", tooLong(maxNameLength))
+	}
+	for i, slide := range doc.Slides {
+		path := fmt.Sprintf("slides[%d]", i)
+		if slide == nil {
+			report(path, ErrMissingSlide)
+			continue
+		}
+
+		err := svc.ValidateSlide(&model.Slide{Type: slide.Type, Content: slide.Content})
+		switch {
+		case errors.Is(err, ErrUnknownSlideType):
+			report(path+".type", err)
+		case err != nil:
+			report(path+".content", err)
+		}
+		if slide.Content == nil {
+			continue
+		}
+		for _, field := range textFields(slide.Content) {
+			if utf8.RuneCountInString(field.text) > field.max {
+				report(path+".content."+field.path, tooLong(field.max))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &DocumentError{Problems: problems}
+	}
+	return nil
+}
+
+// textField is a text of a slide along with the length of its column
+type textField struct {
+	path string
+	text string
+	max  int
+}
+
+// textFields lists the texts of a content that are stored, so that those too
+// long for their column are reported rather than failing to be stored
+func textFields(content *model.Content) []textField {
+	fields := []textField{
+		{"title", content.Title, maxTitleLength},
+		{"meta", content.Meta, maxMetaLength},
+		{"image", content.Image, maxUrlLength},
+	}
+	for i, option := range content.Options {
+		if option != nil {
+			path := fmt.Sprintf("options[%d].", i)
+			fields = append(fields,
+				textField{path + "name", option.Name, maxTitleLength},
+				textField{path + "image", option.Image, maxImageLength},
+			)
+		}
+	}
+
+	if c := content.Heading; c != nil {
+		fields = append(fields,
+			textField{"heading.heading", c.Heading, maxTitleLength},
+			textField{"heading.sub_heading", c.SubHeading, maxCaptionLength},
+			textField{"heading.image", c.Image, maxImageLength},
+		)
+	}
+	if c := content.Paragraph; c != nil {
+		fields = append(fields,
+			textField{"paragraph.heading", c.Heading, maxTitleLength},
+			textField{"paragraph.text", c.Text, maxTextLength},
+			textField{"paragraph.image", c.Image, maxImageLength},
+		)
+	}
+	if c := content.Bullets; c != nil {
+		fields = append(fields,
+			textField{"bullets.heading", c.Heading, maxTitleLength},
+			textField{"bullets.image", c.Image, maxImageLength},
+		)
+	}
+	if c := content.ImageSlide; c != nil {
+		fields = append(fields,
+			textField{"image_slide.heading", c.Heading, maxTitleLength},
+			textField{"image_slide.url", c.Url, maxUrlLength},
+			textField{"image_slide.caption", c.Caption, maxCaptionLength},
+		)
+	}
+	if c := content.Video; c != nil {
+		fields = append(fields,
+			textField{"video.heading", c.Heading, maxTitleLength},
+			textField{"video.url", c.Url, maxUrlLength},
+		)
+	}
+	if c := content.Big; c != nil {
+		fields = append(fields,
+			textField{"big.text", c.Text, maxTitleLength},
+			textField{"big.sub_text", c.SubText, maxCaptionLength},
+		)
+	}
+	if c := content.Quote; c != nil {
+		fields = append(fields,
+			textField{"quote.quote", c.Quote, maxTextLength},
+			textField{"quote.author", c.Author, maxTitleLength},
+			textField{"quote.image", c.Image, maxImageLength},
+		)
+	}
+	if c := content.Number; c != nil {
+		fields = append(fields,
+			textField{"number.number", c.Number, maxLabelLength},
+			textField{"number.caption", c.Caption, maxCaptionLength},
+		)
+	}
+	if c := content.Instructions; c != nil {
+		fields = append(fields,
+			textField{"instructions.heading", c.Heading, maxTitleLength},
+			textField{"instructions.text", c.Text, maxTextLength},
+		)
+	}
+	if c := content.Scale; c != nil {
+		fields = append(fields,
+			textField{"scale.min_label", c.MinLabel, maxLabelLength},
+			textField{"scale.max_label", c.MaxLabel, maxLabelLength},
+		)
+	}
+	if c := content.GridAxes; c != nil {
+		fields = append(fields,
+			textField{"grid_axes.x_label", c.XLabel, maxLabelLength},
+			textField{"grid_axes.y_label", c.YLabel, maxLabelLength},
+		)
+	}
+	return fields
+}
+
+func tooLong(max int) error {
+	return fmt.Errorf("%w, at most %d characters", ErrTextTooLong, max)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"database/sql"
+	"errors"
+)
+
+const maxGridSpan = 1000
+
+var (
+	ErrInvalidGrid      = errors.New("grid axes ranges are invalid")
+	ErrInvalidGridPoint = errors.New("points must place items of the slide within the grid axes")
+)
+
+func defaultGridAxes() *model.GridAxes {
+	return &model.GridAxes{XMin: 0, XMax: 10, YMin: 0, YMax: 10}
+}
+
+func validateGridAxes(axes *model.GridAxes) error {
+	if axes.XMin >= axes.XMax || axes.XMax-axes.XMin > maxGridSpan {
+		return ErrInvalidGrid
+	}
+	if axes.YMin >= axes.YMax || axes.YMax-axes.YMin > maxGridSpan {
+		return ErrInvalidGrid
+	}
+	return nil
+}
+
+// createGrid stores the items of a 2x2 grid slide and its axes, both ranging
+// from 0 to 10 unless specified
+func (svc *slideService) createGrid(contentId string, content *model.Content) error {
+	if err := svc.slideRepo.InsertOption(contentId, content.Options); err != nil {
+		return err
+	}
+	axes := content.GridAxes
+	if axes == nil {
+		axes = defaultGridAxes()
+	}
+
+	return svc.slideRepo.UpsertGridAxes(contentId, axes)
+}
+
+func (svc *slideService) updateGrid(contentId string, content *model.Content) error {
+	if _, err := svc.slideRepo.UpdateOptions(contentId, content.Options); err != nil {
+		return err
+	}
+	if content.GridAxes == nil {
+		return nil
+	}
+
+	return svc.slideRepo.UpsertGridAxes(contentId, content.GridAxes)
+}
+
+// SubmitGridPoints stores where a participant places the items of a 2x2 grid
+// slide. Placing an item again moves it, unless the slide does not allow
+// changing votes
+func (svc *slideService) SubmitGridPoints(contentId, participantId string, points []*model.GridPoint) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	if len(points) == 0 {
+		return ErrInvalidGridPoint
+	}
+
+	axes, err := svc.findGridAxes(contentId)
+	if err != nil {
+		return err
+	}
+
+	items, err := svc.slideRepo.FindOptionIds(contentId)
+	if err != nil {
+		return err
+	}
+	isItem := make(map[uint]bool, len(items))
+	for _, id := range items {
+		isItem[id] = true
+	}
+
+	placed := make(map[uint]bool, len(points))
+	for _, point := range points {
+		if point == nil || !isItem[point.OptionId] || placed[point.OptionId] {
+			return ErrInvalidGridPoint
+		}
+		if point.X < axes.XMin || point.X > axes.XMax || point.Y < axes.YMin || point.Y > axes.YMax {
+			return ErrInvalidGridPoint
+		}
+		placed[point.OptionId] = true
+	}
+
+	// The items of a response are placed all together or not at all. The
+	// content is locked first so that two responses of a participant cannot
+	// both pass the check for an earlier one
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Slide.LockContent(contentId); err != nil {
+			return err
+		}
+
+		placedBefore, err := tx.Slide.HasGridPoints(svc.sessionId(), contentId, participantId)
+		if err != nil {
+			return err
+		}
+		if placedBefore {
+			allowChange, err := tx.Slide.FindAllowVoteChange(contentId)
+			if err != nil {
+				return err
+			}
+			if !allowChange {
+				return ErrAlreadyVoted
+			}
+		}
+
+		return tx.Slide.UpsertGridPoints(svc.sessionId(), contentId, participantId, points)
+	})
+}
+
+func (svc *slideService) findGridAxes(contentId string) (*model.GridAxes, error) {
+	axes, err := svc.slideRepo.FindGridAxes(contentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultGridAxes(), nil
+	}
+	return axes, err
+}
+
+// loadGridResults attaches the axes of a 2x2 grid slide along with the mean
+// position and point cloud of every item
+func (svc *slideService) loadGridResults(contentId string, content *model.Content) error {
+	axes, err := svc.findGridAxes(contentId)
+	if err != nil {
+		return err
+	}
+	content.GridAxes = axes
+
+	points, err := svc.slideRepo.FindGridPoints(svc.sessionId(), contentId)
+	if err != nil {
+		return err
+	}
+
+	results := make(map[uint]*model.GridResult, len(content.Options))
+	content.GridResults = nil
+	for _, item := range content.Options {
+		result := &model.GridResult{OptionId: item.Id, Points: []*model.GridPoint{}}
+		results[item.Id] = result
+		content.GridResults = append(content.GridResults, result)
+	}
+
+	for _, point := range points {
+		result, ok := results[point.OptionId]
+		if !ok {
+			continue
+		}
+		result.Count++
+		result.MeanX += float64(point.X)
+		result.MeanY += float64(point.Y)
+		result.Points = append(result.Points, &model.GridPoint{X: point.X, Y: point.Y})
+	}
+
+	for _, result := range content.GridResults {
+		if result.Count > 0 {
+			result.MeanX /= float64(result.Count)
+			result.MeanY /= float64(result.Count)
+		}
+	}
+
+	return nil
+}
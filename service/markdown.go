@@ -0,0 +1,241 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMarkdownSize is the size of the largest Markdown outline imported, in
+// bytes
+const MaxMarkdownSize = 1 << 20
+
+const defaultImportName = "Imported presentation"
+
+var (
+	ErrInvalidMarkdown = errors.New("markdown is too large or holds no slides")
+	ErrHeadingTooLong  = errors.New("heading is too long")
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	markdownListItem = regexp.MustCompile(`^ {0,3}(?:[-*+]|\d{1,9}[.)])[ \t]+(.*)$`)
+	markdownFence    = regexp.MustCompile("^ {0,3}(?:```|~~~)")
+)
+
+// ImportMarkdown recreates a presentation for the user out of a Markdown
+// outline. It is named name, or after the first top level heading of the
+// outline when empty
+func (svc *slideService) ImportMarkdown(markdown, name, userId string) (*model.Pres, error) {
+	if len(markdown) > MaxMarkdownSize {
+		return nil, ErrInvalidMarkdown
+	}
+
+	doc, err := parseMarkdown(markdown)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Slides) == 0 {
+		return nil, ErrInvalidMarkdown
+	}
+	if !isBlank(name) {
+		doc.Name = name
+	} else if utf8.RuneCountInString(doc.Name) > maxNameLength {
+		// The top level heading names the presentation
+		return nil, headingTooLong(doc.Name, maxNameLength)
+	}
+	if isBlank(doc.Name) {
+		doc.Name = defaultImportName
+	}
+
+	return svc.ImportPresentation(doc, userId)
+}
+
+// markdownParser turns an outline into slides line by line. Headings become
+// heading slides, and prose up to the next heading paragraph slides. A
+// heading ending with a question mark followed by a list becomes a multiple
+// choice slide with the items of the list as options
+type markdownParser struct {
+	doc *model.PresDocument
+
+	// Question heading waiting for the items of its list
+	question string
+	options  []*model.Option
+
+	prose   []string
+	inFence bool
+
+	// First heading too long to be a slide title
+	err error
+}
+
+func parseMarkdown(markdown string) (*model.PresDocument, error) {
+	p := &markdownParser{doc: &model.PresDocument{Version: DocumentVersion}}
+
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	for _, line := range strings.Split(markdown, "\n") {
+		p.parseLine(line)
+	}
+	p.flush()
+
+	return p.doc, p.err
+}
+
+func (p *markdownParser) parseLine(line string) {
+	// Fenced code is prose, whatever it looks like
+	if p.inFence {
+		p.prose = append(p.prose, line)
+		p.inFence = !markdownFence.MatchString(line)
+		return
+	}
+	if markdownFence.MatchString(line) {
+		p.flushQuestion()
+		p.prose = append(p.prose, line)
+		p.inFence = true
+		return
+	}
+
+	if match := markdownHeading.FindStringSubmatch(line); match != nil {
+		p.flush()
+		text := strings.TrimSpace(match[2])
+		if p.err == nil && utf8.RuneCountInString(text) > maxTitleLength {
+			p.err = headingTooLong(text, maxTitleLength)
+		}
+		if len(match[1]) == 1 && p.doc.Name == "" {
+			p.doc.Name = text
+		}
+		if strings.HasSuffix(text, "?") {
+			p.question = text
+		} else {
+			p.addSlide(model.SlideTypeHeading, &model.Content{
+				Title:   text,
+				Heading: &model.Heading{Heading: text},
+			})
+		}
+		return
+	}
+
+	if p.question != "" {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		if match := markdownListItem.FindStringSubmatch(line); match != nil {
+			p.options = append(p.options, &model.Option{Name: strings.TrimSpace(match[1])})
+			return
+		}
+		if len(p.options) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			// Continuation of the last item
+			last := p.options[len(p.options)-1]
+			last.Name += " " + strings.TrimSpace(line)
+			return
+		}
+		p.flushQuestion()
+	}
+
+	if len(p.prose) > 0 || strings.TrimSpace(line) != "" {
+		p.prose = append(p.prose, line)
+	}
+}
+
+func (p *markdownParser) flush() {
+	p.flushQuestion()
+	p.flushProse()
+}
+
+// flushQuestion adds the slide of the open question heading, a heading slide
+// when no list followed it
+func (p *markdownParser) flushQuestion() {
+	if p.question == "" {
+		return
+	}
+
+	if len(p.options) > 0 {
+		p.addSlide(model.SlideTypeMultipleChoice, &model.Content{
+			Title:   p.question,
+			Options: p.options,
+		})
+	} else {
+		p.addSlide(model.SlideTypeHeading, &model.Content{
+			Title:   p.question,
+			Heading: &model.Heading{Heading: p.question},
+		})
+	}
+	p.question = ""
+	p.options = nil
+}
+
+// flushProse adds the prose read since the last heading as paragraph slides,
+// as many as it takes for each to fit a paragraph
+func (p *markdownParser) flushProse() {
+	text := strings.TrimSpace(strings.Join(p.prose, "\n"))
+	p.prose = nil
+	p.inFence = false
+	if text == "" {
+		return
+	}
+
+	for _, chunk := range splitProse(text, maxTextLength) {
+		p.addSlide(model.SlideTypeParagraph, &model.Content{
+			Paragraph: &model.Paragraph{Text: chunk},
+		})
+	}
+}
+
+// splitProse cuts text into chunks of at most max characters, between its
+// paragraphs when possible, else between its words
+func splitProse(text string, max int) []string {
+	var chunks []string
+	var chunk string
+	add := func(piece, sep string) {
+		switch {
+		case chunk == "":
+			chunk = piece
+		case utf8.RuneCountInString(chunk)+len(sep)+utf8.RuneCountInString(piece) <= max:
+			chunk += sep + piece
+		default:
+			chunks = append(chunks, chunk)
+			chunk = piece
+		}
+	}
+
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if utf8.RuneCountInString(paragraph) <= max {
+			add(paragraph, "\n\n")
+			continue
+		}
+
+		// A paragraph too long on its own is cut between its words, and
+		// words too long on their own are cut anywhere
+		sep := "\n\n"
+		for _, word := range strings.Fields(paragraph) {
+			for runes := []rune(word); len(runes) > 0; {
+				n := len(runes)
+				if n > max {
+					n = max
+				}
+				add(string(runes[:n]), sep)
+				runes = runes[n:]
+				sep = " "
+			}
+		}
+	}
+	if chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func headingTooLong(heading string, max int) error {
+	return fmt.Errorf("%w, at most %d characters: %q", ErrHeadingTooLong, max, heading)
+}
+
+func (p *markdownParser) addSlide(slideType uint, content *model.Content) {
+	p.doc.Slides = append(p.doc.Slides, &model.DocumentSlide{Type: slideType, Content: content})
+}
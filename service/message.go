@@ -0,0 +1,76 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultMessagePageSize = 50
+	MaxMessagePageSize     = 100
+
+	// Number of messages replayed to clients joining a room
+	messageBacklogSize = 50
+
+	maxMessageLength = 250
+	anonymousName    = "Anonymous"
+)
+
+var ErrInvalidMessage = errors.New("message is empty or too long")
+
+type IMessageService interface {
+	GetMessages(presId string, beforeId uint, limit int) ([]*model.Message, error)
+	GetBacklog(presId string) ([]*model.Message, error)
+	SendMessage(message *model.Message) error
+}
+
+type messageService struct {
+	messageRepo repository.IMessageRepo
+}
+
+func NewMessageService(messageRepo repository.IMessageRepo) *messageService {
+	return &messageService{
+		messageRepo: messageRepo,
+	}
+}
+
+// GetMessages returns a page of the chat history of a presentation in
+// chronological order, made of the messages sent before beforeId
+func (svc *messageService) GetMessages(presId string, beforeId uint, limit int) ([]*model.Message, error) {
+	if limit <= 0 {
+		limit = DefaultMessagePageSize
+	}
+	if limit > MaxMessagePageSize {
+		limit = MaxMessagePageSize
+	}
+
+	messages, err := svc.messageRepo.FindMessagesBefore(presId, beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// GetBacklog returns the most recent messages of a presentation
+func (svc *messageService) GetBacklog(presId string) ([]*model.Message, error) {
+	return svc.GetMessages(presId, 0, messageBacklogSize)
+}
+
+func (svc *messageService) SendMessage(message *model.Message) error {
+	message.Message = strings.TrimSpace(message.Message)
+	if message.Message == "" || utf8.RuneCountInString(message.Message) > maxMessageLength {
+		return ErrInvalidMessage
+	}
+	if message.Username == "" {
+		message.Username = anonymousName
+	}
+
+	return svc.messageRepo.InsertMessage(message)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxAnswerLength = 500
+
+var (
+	ErrInvalidAnswer  = errors.New("answer is empty or too long")
+	ErrAnswerNotFound = errors.New("answer not found")
+)
+
+// SubmitAnswer stores the free-text answer of a participant to an open ended
+// slide. author is the display name of logged in participants, empty for
+// anonymous ones
+func (svc *slideService) SubmitAnswer(contentId, participantId, author, text string) (*model.OpenEndedAnswer, error) {
+	if err := svc.requireSession(); err != nil {
+		return nil, err
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxAnswerLength {
+		return nil, ErrInvalidAnswer
+	}
+
+	answer := &model.OpenEndedAnswer{
+		SessionId:     svc.session,
+		ContentId:     utils.Str2Uint(contentId),
+		ParticipantId: participantId,
+		Author:        author,
+		Text:          text,
+	}
+	if err := svc.slideRepo.InsertAnswer(answer); err != nil {
+		return nil, err
+	}
+
+	return answer, nil
+}
+
+// ModerateAnswer hides or highlights an answer, leaving the flags missing
+// from the moderation untouched
+func (svc *slideService) ModerateAnswer(contentId, answerId string, moderation model.AnswerModeration) (*model.OpenEndedAnswer, error) {
+	answer, err := svc.slideRepo.FindAnswerById(contentId, answerId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAnswerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if moderation.IsHidden != nil {
+		answer.IsHidden = *moderation.IsHidden
+	}
+	if moderation.IsHighlighted != nil {
+		answer.IsHighlighted = *moderation.IsHighlighted
+	}
+
+	if _, err = svc.slideRepo.UpdateAnswerModeration(answer); err != nil {
+		return nil, err
+	}
+
+	return answer, nil
+}
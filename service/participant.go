@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/thanhpk/randstr"
+)
+
+const (
+	userParticipantPrefix = "user:"
+	anonParticipantPrefix = "anon:"
+)
+
+// IParticipantService identifies who takes part in a presentation. Logged in
+// users are identified by their user id, anonymous audience members by a
+// random id signed by the server so that it cannot be forged to vote on
+// behalf of someone else
+type IParticipantService interface {
+	ForUser(userId string) string
+	NewAnonymous() string
+	VerifyAnonymous(participantId string) bool
+}
+
+type participantService struct {
+	secretKey string
+}
+
+func NewParticipantService() *participantService {
+	return &participantService{
+		secretKey: getSecretKey(),
+	}
+}
+
+func (svc *participantService) ForUser(userId string) string {
+	return userParticipantPrefix + userId
+}
+
+func (svc *participantService) NewAnonymous() string {
+	token := randstr.Hex(16)
+	return anonParticipantPrefix + token + "." + svc.sign(token)
+}
+
+func (svc *participantService) VerifyAnonymous(participantId string) bool {
+	if !strings.HasPrefix(participantId, anonParticipantPrefix) {
+		return false
+	}
+
+	token, signature, found := strings.Cut(strings.TrimPrefix(participantId, anonParticipantPrefix), ".")
+	if !found {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(svc.sign(token)))
+}
+
+func (svc *participantService) sign(token string) string {
+	mac := hmac.New(sha256.New, []byte(svc.secretKey))
+	mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+	"errors"
+	"math"
+)
+
+const (
+	DefaultHeatmapResolution = 10
+	MaxHeatmapResolution     = 100
+)
+
+var (
+	ErrInvalidPin        = errors.New("pin must be within the image, from 0 to 1 on both axes")
+	ErrInvalidResolution = errors.New("heatmap resolution is out of range")
+	ErrNotPinOnImage     = errors.New("slide is not a pin on image slide")
+)
+
+// SubmitPin stores the pin a participant drops on the image of a pin on image
+// slide. Dropping it again moves it
+func (svc *slideService) SubmitPin(contentId, participantId string, pin *model.Pin) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	if pin == nil || math.IsNaN(pin.X) || math.IsNaN(pin.Y) {
+		return ErrInvalidPin
+	}
+	if pin.X < 0 || pin.X > 1 || pin.Y < 0 || pin.Y > 1 {
+		return ErrInvalidPin
+	}
+
+	return svc.slideRepo.UpsertPin(svc.sessionId(), contentId, participantId, pin)
+}
+
+// GetHeatmap bins the pins of a pin on image slide at the given resolution
+func (svc *slideService) GetHeatmap(slide *model.Slide, resolution int) (*model.Heatmap, error) {
+	if slide.Type != model.SlideTypePinOnImage {
+		return nil, ErrNotPinOnImage
+	}
+	if resolution < 1 || resolution > MaxHeatmapResolution {
+		return nil, ErrInvalidResolution
+	}
+
+	pins, err := svc.slideRepo.FindPins(svc.sessionId(), utils.Uint2Str(slide.Content.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &model.Heatmap{Resolution: resolution, Pins: uint(len(pins)), Cells: make([][]uint, resolution)}
+	for row := range heatmap.Cells {
+		heatmap.Cells[row] = make([]uint, resolution)
+	}
+	for _, pin := range pins {
+		heatmap.Cells[heatmapBin(pin.Y, resolution)][heatmapBin(pin.X, resolution)]++
+	}
+
+	return heatmap, nil
+}
+
+// heatmapBin is the cell a normalized coordinate falls in, the coordinate 1
+// belonging to the last cell
+func heatmapBin(coordinate float64, resolution int) int {
+	bin := int(coordinate * float64(resolution))
+	if bin >= resolution {
+		return resolution - 1
+	}
+	if bin < 0 {
+		return 0
+	}
+	return bin
+}
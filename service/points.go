@@ -0,0 +1,116 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"errors"
+	"sort"
+)
+
+// PointsBudget is the number of points each participant splits across the
+// options of a 100 points slide
+const PointsBudget = 100
+
+var ErrInvalidAllocation = errors.New("allocation must give options of the slide points adding up to the budget")
+
+// SubmitAllocation stores how a participant splits the budget across the
+// options of a 100 points slide. Options left out get no points
+func (svc *slideService) SubmitAllocation(contentId, participantId string, allocations []*model.Allocation) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	if len(allocations) == 0 {
+		return ErrInvalidAllocation
+	}
+
+	options, err := svc.slideRepo.FindOptionIds(contentId)
+	if err != nil {
+		return err
+	}
+	isOption := make(map[uint]bool, len(options))
+	for _, id := range options {
+		isOption[id] = true
+	}
+
+	sum := 0
+	allocated := make(map[uint]bool, len(allocations))
+	for _, allocation := range allocations {
+		if allocation == nil || !isOption[allocation.OptionId] || allocated[allocation.OptionId] {
+			return ErrInvalidAllocation
+		}
+		if allocation.Points < 0 || allocation.Points > PointsBudget {
+			return ErrInvalidAllocation
+		}
+		allocated[allocation.OptionId] = true
+		sum += allocation.Points
+	}
+	if sum != PointsBudget {
+		return ErrInvalidAllocation
+	}
+
+	// The previous allocation is replaced as a whole or not at all. The content
+	// is locked first so that two allocations of a participant cannot both pass
+	// the check for an earlier one
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Slide.LockContent(contentId); err != nil {
+			return err
+		}
+
+		allocatedBefore, err := tx.Slide.HasAllocation(svc.sessionId(), contentId, participantId)
+		if err != nil {
+			return err
+		}
+		if allocatedBefore {
+			allowChange, err := tx.Slide.FindAllowVoteChange(contentId)
+			if err != nil {
+				return err
+			}
+			if !allowChange {
+				return ErrAlreadyVoted
+			}
+		}
+
+		return tx.Slide.ReplaceAllocation(svc.sessionId(), contentId, participantId, allocations)
+	})
+}
+
+// loadPointsResults sums up the points given to every option of a 100 points
+// slide and averages them over the ballots
+func (svc *slideService) loadPointsResults(contentId string, content *model.Content) error {
+	totals, err := svc.slideRepo.FindPointTotals(svc.sessionId(), contentId)
+	if err != nil {
+		return err
+	}
+
+	ballots, err := svc.slideRepo.CountAllocationBallots(svc.sessionId(), contentId)
+	if err != nil {
+		return err
+	}
+
+	points := make(map[uint]*model.OptionPoints, len(content.Options))
+	result := &model.PointsResult{Budget: PointsBudget, Ballots: ballots}
+	for _, option := range content.Options {
+		total := &model.OptionPoints{OptionId: option.Id}
+		points[option.Id] = total
+		result.Totals = append(result.Totals, total)
+	}
+
+	for _, total := range totals {
+		if option, ok := points[total.OptionId]; ok {
+			option.Total = total.Total
+		}
+	}
+	if ballots > 0 {
+		for _, total := range result.Totals {
+			total.Average = float64(total.Total) / float64(ballots)
+		}
+	}
+
+	sort.SliceStable(result.Totals, func(i, j int) bool {
+		return result.Totals[i].Total > result.Totals[j].Total
+	})
+
+	content.Points = result
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+)
+
+// Points scored by a correct prediction
+const predictionPoints = 1000
+
+var ErrPredictionResolved = errors.New("winner was already revealed")
+
+// SubmitPrediction stores the prediction of a participant on a who will win
+// slide, as long as the winner was not revealed
+func (svc *slideService) SubmitPrediction(contentId, optionId, participantId string) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	// The content is locked so that the winner cannot be revealed between the
+	// check and the vote
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		err := tx.Slide.LockContent(contentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOptionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		txSvc := svc.within(tx)
+		result, err := txSvc.findPredictionResult(contentId)
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			return ErrPredictionResolved
+		}
+
+		return txSvc.vote(model.SlideTypeWhoWillWin, contentId, optionId, participantId)
+	})
+}
+
+// ResolvePrediction reveals the winner of a who will win slide, closing the
+// predictions and scoring the participants who predicted it. Revealing
+// another winner corrects the scores
+func (svc *slideService) ResolvePrediction(contentId, optionId string) (*model.PredictionResult, error) {
+	if err := svc.requireSession(); err != nil {
+		return nil, err
+	}
+
+	options, err := svc.slideRepo.FindOptionIds(contentId)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, id := range options {
+		if utils.Uint2Str(id) == optionId {
+			found = true
+		}
+	}
+	if !found {
+		return nil, ErrOptionNotFound
+	}
+
+	// The winner is revealed along with the scores or not at all, after the
+	// predictions submitted meanwhile
+	err = svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Slide.LockContent(contentId); err != nil {
+			return err
+		}
+		return tx.Slide.ResolvePrediction(svc.sessionId(), contentId, optionId, predictionPoints)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.findPredictionResult(contentId)
+}
+
+// findPredictionResult returns the outcome of a who will win slide, or nil
+// while the winner is not revealed
+func (svc *slideService) findPredictionResult(contentId string) (*model.PredictionResult, error) {
+	result, err := svc.slideRepo.FindPredictionResult(svc.sessionId(), contentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return result, err
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	QuestionSortVotes  = "votes"
+	QuestionSortRecent = "recent"
+
+	maxQuestionLength = 250
+)
+
+var (
+	ErrInvalidQuestion  = errors.New("question is empty or too long")
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrAlreadyUpvoted   = errors.New("participant already upvoted this question")
+)
+
+type IQuestionService interface {
+	GetQuestions(presId, sortBy string) ([]*model.Question, error)
+	AskQuestion(question *model.Question) error
+	UpvoteQuestion(presId, questionId, userId, participantId string) (*model.Question, error)
+	MarkAnswered(presId, questionId string, answered bool) (*model.Question, error)
+}
+
+type questionService struct {
+	questionRepo repository.IQuestionRepo
+	uow          repository.IUnitOfWork
+}
+
+func NewQuestionService(questionRepo repository.IQuestionRepo, uow repository.IUnitOfWork) *questionService {
+	return &questionService{
+		questionRepo: questionRepo,
+		uow:          uow,
+	}
+}
+
+// GetQuestions lists the questions of a presentation, most upvoted first or
+// most recent first
+func (svc *questionService) GetQuestions(presId, sortBy string) ([]*model.Question, error) {
+	if sortBy == QuestionSortVotes {
+		return svc.questionRepo.FindQuestionsByVotes(presId)
+	}
+	return svc.questionRepo.FindQuestionsByRecency(presId)
+}
+
+func (svc *questionService) AskQuestion(question *model.Question) error {
+	question.Question = strings.TrimSpace(question.Question)
+	if question.Question == "" || utf8.RuneCountInString(question.Question) > maxQuestionLength {
+		return ErrInvalidQuestion
+	}
+
+	question.TotalVotes = 0
+	question.IsAnswered = false
+	return svc.questionRepo.InsertQuestion(question)
+}
+
+// UpvoteQuestion adds the upvote of a participant to a question, once per
+// participant
+func (svc *questionService) UpvoteQuestion(presId, questionId, userId, participantId string) (*model.Question, error) {
+	if _, err := svc.findQuestion(presId, questionId); err != nil {
+		return nil, err
+	}
+
+	// The upvote is counted along with its record or not at all
+	err := svc.uow.Do(func(tx *repository.Tx) error {
+		inserted, err := tx.Question.InsertQuestionVote(presId, questionId, userId, participantId)
+		if err != nil {
+			return err
+		}
+		if inserted == 0 {
+			return ErrAlreadyUpvoted
+		}
+
+		_, err = tx.Question.IncreaseQuestionVotes(presId, questionId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.findQuestion(presId, questionId)
+}
+
+func (svc *questionService) MarkAnswered(presId, questionId string, answered bool) (*model.Question, error) {
+	if _, err := svc.findQuestion(presId, questionId); err != nil {
+		return nil, err
+	}
+
+	if _, err := svc.questionRepo.UpdateQuestionAnswered(presId, questionId, answered); err != nil {
+		return nil, err
+	}
+
+	return svc.findQuestion(presId, questionId)
+}
+
+func (svc *questionService) findQuestion(presId, questionId string) (*model.Question, error) {
+	question, err := svc.questionRepo.FindQuestionById(presId, questionId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrQuestionNotFound
+	}
+	return question, err
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// Time limits of quiz questions, in seconds
+	DefaultQuizTimeLimit = 20
+	MaxQuizTimeLimit     = 600
+
+	// A correct answer earns the base points, plus up to the speed points
+	// the faster it is given
+	quizBasePoints  = 500
+	quizSpeedPoints = 500
+
+	leaderboardSize     = 10
+	maxQuizAnswerLength = 250
+)
+
+// Policies matching typed answers against the accepted answers
+const (
+	AnswerPolicyExact      = "exact"
+	AnswerPolicyNormalized = "normalized"
+	AnswerPolicyFuzzy      = "fuzzy"
+
+	defaultMaxDistance = 2
+	maxMaxDistance     = 10
+
+	// Fuzzy answers are allowed an edit per this many runes of the accepted
+	// answer, so that short answers must match exactly
+	runesPerEdit = 4
+)
+
+var (
+	ErrInvalidQuiz       = errors.New("quiz needs a correct answer and a time limit within bounds")
+	ErrInvalidQuizAnswer = errors.New("answer must pick an option of the question or be a non-empty text")
+	ErrAlreadyAnswered   = errors.New("participant already answered this question")
+	ErrInvalidPolicy     = errors.New("answer policy must be exact, normalized or fuzzy within a small distance")
+)
+
+type IQuizService interface {
+	SubmitQuizAnswer(slide *model.Slide, participantId, username string, optionId uint, text string, elapsed time.Duration) (*model.QuizResponse, error)
+	GetLeaderboard(presId string) ([]*model.LeaderboardEntry, error)
+	GetTally(slide *model.Slide) (*model.QuizTally, error)
+	InSession(sessionId uint) IQuizService
+}
+
+type quizService struct {
+	quizRepo repository.IQuizRepo
+	session  uint
+}
+
+func NewQuizService(quizRepo repository.IQuizRepo) *quizService {
+	return &quizService{
+		quizRepo: quizRepo,
+	}
+}
+
+// InSession is the service recording the answers of a session and scoring
+// that session only
+func (svc *quizService) InSession(sessionId uint) IQuizService {
+	return &quizService{
+		quizRepo: svc.quizRepo,
+		session:  sessionId,
+	}
+}
+
+// IsQuiz tells whether a slide type is a timed quiz question
+func IsQuiz(slideType uint) bool {
+	return slideType == model.SlideTypeSelectAnswer || slideType == model.SlideTypeTypeAnswer
+}
+
+// QuizTimeLimit is the time the audience has to answer a quiz question
+func QuizTimeLimit(content *model.Content) time.Duration {
+	if content.TimeLimit == 0 {
+		return DefaultQuizTimeLimit * time.Second
+	}
+	return time.Duration(content.TimeLimit) * time.Second
+}
+
+// validateQuiz checks a quiz question. Select answer questions pick among at
+// least two options of which one or more are correct, while the options of
+// type answer questions are the accepted answers
+func validateQuiz(slideType uint, content *model.Content) error {
+	if content.TimeLimit > MaxQuizTimeLimit {
+		return ErrInvalidQuiz
+	}
+
+	correct := 0
+	for _, option := range content.Options {
+		if option == nil || strings.TrimSpace(option.Name) == "" {
+			return ErrInvalidQuiz
+		}
+		if option.IsCorrect || slideType == model.SlideTypeTypeAnswer {
+			correct++
+		}
+	}
+
+	if correct == 0 || (slideType == model.SlideTypeSelectAnswer && len(content.Options) < 2) {
+		return ErrInvalidQuiz
+	}
+	return nil
+}
+
+func defaultAnswerPolicy() *model.AnswerPolicy {
+	return &model.AnswerPolicy{Policy: AnswerPolicyNormalized}
+}
+
+func validateAnswerPolicy(policy *model.AnswerPolicy) error {
+	switch policy.Policy {
+	case AnswerPolicyExact, AnswerPolicyNormalized:
+		policy.MaxDistance = 0
+	case AnswerPolicyFuzzy:
+		if policy.MaxDistance == 0 {
+			policy.MaxDistance = defaultMaxDistance
+		}
+		if policy.MaxDistance < 0 || policy.MaxDistance > maxMaxDistance {
+			return ErrInvalidPolicy
+		}
+	default:
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// validateQuizContent checks a quiz question along with the answer policy of
+// type answer questions
+func validateQuizContent(slideType uint, content *model.Content) error {
+	if err := validateQuiz(slideType, content); err != nil {
+		return err
+	}
+	if slideType == model.SlideTypeTypeAnswer && content.AnswerPolicy != nil {
+		return validateAnswerPolicy(content.AnswerPolicy)
+	}
+	return nil
+}
+
+// createQuiz stores the options of a quiz question, along with the answer
+// policy of type answer questions, ignoring case and diacritics unless
+// specified
+func (svc *slideService) createQuiz(contentId string, slideType uint, content *model.Content) error {
+	markAccepted(slideType, content)
+	if err := svc.slideRepo.InsertOption(contentId, content.Options); err != nil {
+		return err
+	}
+	if slideType != model.SlideTypeTypeAnswer {
+		return nil
+	}
+
+	if content.AnswerPolicy == nil {
+		content.AnswerPolicy = defaultAnswerPolicy()
+	}
+	return svc.slideRepo.UpsertAnswerPolicy(contentId, content.AnswerPolicy)
+}
+
+func (svc *slideService) updateQuiz(contentId string, slideType uint, content *model.Content) error {
+	markAccepted(slideType, content)
+	if slideType == model.SlideTypeTypeAnswer && content.AnswerPolicy != nil {
+		if err := svc.slideRepo.UpsertAnswerPolicy(contentId, content.AnswerPolicy); err != nil {
+			return err
+		}
+	}
+
+	_, err := svc.slideRepo.UpdateOptions(contentId, content.Options)
+	return err
+}
+
+// markAccepted marks the options of a type answer question as correct, each
+// of them being an accepted answer
+func markAccepted(slideType uint, content *model.Content) {
+	if slideType != model.SlideTypeTypeAnswer {
+		return
+	}
+	for _, option := range content.Options {
+		option.IsCorrect = true
+	}
+}
+
+func (svc *slideService) findAnswerPolicy(contentId string) (*model.AnswerPolicy, error) {
+	policy, err := svc.slideRepo.FindAnswerPolicy(contentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultAnswerPolicy(), nil
+	}
+	return policy, err
+}
+
+// SubmitQuizAnswer scores and records the answer of a participant to a quiz
+// question, given the time it took since the question was opened. Each
+// participant answers a question once per session
+func (svc *quizService) SubmitQuizAnswer(slide *model.Slide, participantId, username string, optionId uint, text string, elapsed time.Duration) (*model.QuizResponse, error) {
+	if svc.session == 0 {
+		return nil, ErrNoSession
+	}
+
+	response := &model.QuizResponse{
+		SessionId:     svc.session,
+		ContentId:     slide.Content.Id,
+		ParticipantId: participantId,
+		Username:      username,
+		ResponseMs:    uint(elapsed.Milliseconds()),
+	}
+	if response.Username == "" {
+		response.Username = anonymousName
+	}
+
+	switch slide.Type {
+	case model.SlideTypeSelectAnswer:
+		option := findOption(slide.Content.Options, optionId)
+		if option == nil {
+			return nil, ErrInvalidQuizAnswer
+		}
+		response.OptionId = option.Id
+		response.IsCorrect = option.IsCorrect
+	case model.SlideTypeTypeAnswer:
+		response.Answer = strings.TrimSpace(text)
+		if response.Answer == "" || utf8.RuneCountInString(response.Answer) > maxQuizAnswerLength {
+			return nil, ErrInvalidQuizAnswer
+		}
+		policy := slide.Content.AnswerPolicy
+		if policy == nil {
+			policy = defaultAnswerPolicy()
+		}
+		response.IsCorrect = acceptsAnswer(policy, slide.Content.Options, response.Answer)
+	default:
+		return nil, ErrInvalidQuizAnswer
+	}
+
+	if response.IsCorrect {
+		response.Points = quizPoints(elapsed, QuizTimeLimit(slide.Content))
+	}
+
+	inserted, err := svc.quizRepo.InsertQuizResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	if inserted == 0 {
+		return nil, ErrAlreadyAnswered
+	}
+
+	return response, nil
+}
+
+func (svc *quizService) GetLeaderboard(presId string) ([]*model.LeaderboardEntry, error) {
+	return svc.quizRepo.FindLeaderboard(presId, utils.Uint2Str(svc.session), leaderboardSize)
+}
+
+// GetTally counts the responses to a quiz question. Typed answers are
+// grouped by their normalized form so that variants of an answer add up
+func (svc *quizService) GetTally(slide *model.Slide) (*model.QuizTally, error) {
+	counts, err := svc.quizRepo.FindQuizTally(utils.Uint2Str(svc.session), slide.Content.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tally := &model.QuizTally{SlideId: slide.Id, Answers: []*model.AnswerCount{}}
+	grouped := make(map[string]*model.AnswerCount, len(counts))
+	for _, count := range counts {
+		tally.Responses += count.Count
+		if count.IsCorrect {
+			tally.Correct += count.Count
+		}
+
+		key := utils.Uint2Str(count.OptionId)
+		if slide.Type == model.SlideTypeTypeAnswer {
+			key = NormalizeAnswer(count.Answer)
+		}
+		if count.IsCorrect {
+			key += "/correct"
+		}
+		if group, ok := grouped[key]; ok {
+			group.Count += count.Count
+			continue
+		}
+		grouped[key] = count
+		tally.Answers = append(tally.Answers, count)
+	}
+
+	return tally, nil
+}
+
+// quizPoints rewards a correct answer, the more the sooner it is given
+func quizPoints(elapsed, limit time.Duration) uint {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed > limit {
+		elapsed = limit
+	}
+	remaining := float64(limit-elapsed) / float64(limit)
+	return quizBasePoints + uint(remaining*quizSpeedPoints+0.5)
+}
+
+func findOption(options []*model.Option, optionId uint) *model.Option {
+	for _, option := range options {
+		if option.Id == optionId {
+			return option
+		}
+	}
+	return nil
+}
+
+// acceptsAnswer tells whether a typed answer matches one of the accepted
+// answers under the policy of the slide. Answers left empty once normalized,
+// such as punctuation only, match none
+func acceptsAnswer(policy *model.AnswerPolicy, accepted []*model.Option, answer string) bool {
+	if policy.Policy != AnswerPolicyExact {
+		answer = NormalizeAnswer(answer)
+		if answer == "" {
+			return false
+		}
+	}
+
+	for _, option := range accepted {
+		expected := strings.TrimSpace(option.Name)
+		switch policy.Policy {
+		case AnswerPolicyExact:
+			if expected == answer {
+				return true
+			}
+		case AnswerPolicyFuzzy:
+			expected = NormalizeAnswer(expected)
+			if editDistance(expected, answer) <= maxEdits(policy, expected) {
+				return true
+			}
+		default:
+			if NormalizeAnswer(expected) == answer {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maxEdits is the edit distance a fuzzy answer may be off an accepted answer,
+// the distance of the policy scaled down for short answers
+func maxEdits(policy *model.AnswerPolicy, expected string) int {
+	return minInt(policy.MaxDistance, utf8.RuneCountInString(expected)/runesPerEdit)
+}
+
+// NormalizeAnswer lowercases an answer and strips it of diacritics and
+// punctuation, collapsing the spaces between its words
+func NormalizeAnswer(answer string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(answer) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// editDistance is the Levenshtein distance between two strings, in runes
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	prev := make([]int, len(target)+1)
+	curr := make([]int, len(target)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		curr[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(target)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"errors"
+	"sort"
+)
+
+var ErrInvalidRanking = errors.New("ranking must order every option of the slide exactly once")
+
+// SubmitRanking stores the ballot of a participant on a ranking slide, the
+// ids of all the options of the slide from most to least preferred
+func (svc *slideService) SubmitRanking(contentId, participantId string, ranking []uint) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	options, err := svc.slideRepo.FindOptionIds(contentId)
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 || len(ranking) != len(options) {
+		return ErrInvalidRanking
+	}
+
+	remaining := make(map[uint]bool, len(options))
+	for _, id := range options {
+		remaining[id] = true
+	}
+	for _, id := range ranking {
+		if !remaining[id] {
+			return ErrInvalidRanking
+		}
+		delete(remaining, id)
+	}
+
+	// The previous ballot is replaced as a whole or not at all. The content is
+	// locked first so that two ballots of a participant cannot both pass the
+	// check for an earlier one
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Slide.LockContent(contentId); err != nil {
+			return err
+		}
+
+		voted, err := tx.Slide.HasRankingBallot(svc.sessionId(), contentId, participantId)
+		if err != nil {
+			return err
+		}
+		if voted {
+			allowChange, err := tx.Slide.FindAllowVoteChange(contentId)
+			if err != nil {
+				return err
+			}
+			if !allowChange {
+				return ErrAlreadyVoted
+			}
+		}
+
+		return tx.Slide.ReplaceRankingBallot(svc.sessionId(), contentId, participantId, ranking)
+	})
+}
+
+// loadRankingResults computes the Borda count of a ranking slide: on a ballot
+// of n options, the option ranked at position p scores n - 1 - p points
+func (svc *slideService) loadRankingResults(contentId string, content *model.Content) error {
+	counts, err := svc.slideRepo.FindPositionCounts(svc.sessionId(), contentId)
+	if err != nil {
+		return err
+	}
+
+	ballots, err := svc.slideRepo.CountBallots(svc.sessionId(), contentId)
+	if err != nil {
+		return err
+	}
+
+	n := len(content.Options)
+	scores := make(map[uint]*model.BordaScore, n)
+	result := &model.RankingResult{Ballots: ballots}
+	for _, option := range content.Options {
+		score := &model.BordaScore{OptionId: option.Id}
+		scores[option.Id] = score
+		result.Scores = append(result.Scores, score)
+	}
+
+	for _, count := range counts {
+		score, ok := scores[count.OptionId]
+		if !ok || count.Position >= n {
+			continue
+		}
+		score.Score += uint(n-1-count.Position) * count.Count
+	}
+
+	sort.SliceStable(result.Scores, func(i, j int) bool {
+		return result.Scores[i].Score > result.Scores[j].Score
+	})
+
+	content.Ranking = result
+	return nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type IResultsService interface {
+	GetResults(presId string, sessionId uint) (*model.PresResults, error)
+}
+
+type resultsService struct {
+	slideService ISlideService
+	quizService  IQuizService
+	presService  IPresService
+}
+
+func NewResultsService(slideSvc ISlideService, quizSvc IQuizService, presSvc IPresService) *resultsService {
+	return &resultsService{
+		slideService: slideSvc,
+		quizService:  quizSvc,
+		presService:  presSvc,
+	}
+}
+
+// GetResults counts the responses of every slide of a presentation during a
+// session per option, as told by its slide type. Slides collecting no
+// responses are left out
+func (svc *resultsService) GetResults(presId string, sessionId uint) (*model.PresResults, error) {
+	pres, err := svc.presService.GetPresentationById(presId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPresNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slides, err := svc.slideService.InSession(sessionId).GetAllSlides(presId)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := svc.slideService.GetSlideTypes()
+	if err != nil {
+		return nil, err
+	}
+	typeNames := make(map[uint]string)
+	for _, category := range categories {
+		for _, t := range category.Types {
+			typeNames[t.Id] = t.Name
+		}
+	}
+
+	results := &model.PresResults{
+		PresentationId: pres.Id,
+		SessionId:      sessionId,
+		Name:           pres.Name,
+		Slides:         []*model.SlideResults{},
+	}
+	for i, slide := range slides {
+		rows, measure, err := svc.slideRows(slide, sessionId)
+		if err != nil {
+			return nil, err
+		}
+		if rows == nil {
+			continue
+		}
+
+		slideResults := &model.SlideResults{
+			SlideId:  slide.Id,
+			Number:   i + 1,
+			Type:     slide.Type,
+			TypeName: typeNames[slide.Type],
+			Title:    slide.Content.Title,
+			Measure:  measure,
+			Rows:     rows,
+		}
+		for _, row := range rows {
+			slideResults.Total += row.Count
+		}
+		for _, row := range rows {
+			row.Percentage = percentage(row.Count, slideResults.Total)
+		}
+		results.Slides = append(results.Slides, slideResults)
+	}
+
+	return results, nil
+}
+
+// slideRows counts the responses of a slide along with what the counts
+// measure, nil when its type collects none. Quiz responses are kept apart
+// from the slides, they are counted from the tally of the question
+func (svc *resultsService) slideRows(slide *model.Slide, sessionId uint) ([]*model.ResultRow, string, error) {
+	if IsQuiz(slide.Type) {
+		tally, err := svc.quizService.InSession(sessionId).GetTally(slide)
+		if err != nil {
+			return nil, "", err
+		}
+		return tallyRows(slide, tally), "answers", nil
+	}
+
+	t, ok := slideTypes[slide.Type]
+	if !ok || t.rows == nil {
+		return nil, "", nil
+	}
+	rows := t.rows(slide.Content)
+	if rows == nil {
+		rows = []*model.ResultRow{}
+	}
+	return rows, t.measure, nil
+}
+
+// percentage is the share of count in total, rounded to one decimal
+func percentage(count, total uint) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)*1000/float64(total)) / 10
+}
+
+func optionRows(content *model.Content) []*model.ResultRow {
+	rows := make([]*model.ResultRow, 0, len(content.Options))
+	for _, option := range content.Options {
+		rows = append(rows, &model.ResultRow{Label: option.Name, Count: option.TotalVotes})
+	}
+	return rows
+}
+
+func wordRows(content *model.Content) []*model.ResultRow {
+	rows := make([]*model.ResultRow, 0, len(content.Words))
+	for _, word := range content.Words {
+		rows = append(rows, &model.ResultRow{Label: word.Word, Count: word.Count})
+	}
+	return rows
+}
+
+// scaleRows counts the ratings of every statement of a scales slide
+func scaleRows(content *model.Content) []*model.ResultRow {
+	rows := make([]*model.ResultRow, 0, len(content.ScaleResults))
+	for _, result := range content.ScaleResults {
+		rows = append(rows, &model.ResultRow{Label: optionName(content, result.OptionId), Count: result.Count})
+	}
+	return rows
+}
+
+// rankingRows counts the Borda score of every option of a ranking slide
+func rankingRows(content *model.Content) []*model.ResultRow {
+	if content.Ranking == nil {
+		return nil
+	}
+	rows := make([]*model.ResultRow, 0, len(content.Ranking.Scores))
+	for _, score := range content.Ranking.Scores {
+		rows = append(rows, &model.ResultRow{Label: optionName(content, score.OptionId), Count: score.Score})
+	}
+	return rows
+}
+
+// pointsRows counts the points given to every option of a 100 points slide
+func pointsRows(content *model.Content) []*model.ResultRow {
+	if content.Points == nil {
+		return nil
+	}
+	rows := make([]*model.ResultRow, 0, len(content.Points.Totals))
+	for _, total := range content.Points.Totals {
+		rows = append(rows, &model.ResultRow{Label: optionName(content, total.OptionId), Count: total.Total})
+	}
+	return rows
+}
+
+// gridRows counts the placements of every item of a 2x2 grid slide
+func gridRows(content *model.Content) []*model.ResultRow {
+	rows := make([]*model.ResultRow, 0, len(content.GridResults))
+	for _, result := range content.GridResults {
+		rows = append(rows, &model.ResultRow{Label: optionName(content, result.OptionId), Count: result.Count})
+	}
+	return rows
+}
+
+// pinRows counts the pins dropped on the image, a pin on image slide having
+// no options
+func pinRows(content *model.Content) []*model.ResultRow {
+	var pins uint
+	if content.Heatmap != nil {
+		pins = content.Heatmap.Pins
+	}
+	return []*model.ResultRow{{Label: "Pins", Count: pins}}
+}
+
+// tallyRows counts the responses to a quiz question per option, or per typed
+// answer for type answer questions
+func tallyRows(slide *model.Slide, tally *model.QuizTally) []*model.ResultRow {
+	rows := make([]*model.ResultRow, 0, len(tally.Answers))
+	for _, answer := range tally.Answers {
+		label := answer.Answer
+		if slide.Type != model.SlideTypeTypeAnswer {
+			label = optionName(slide.Content, answer.OptionId)
+		}
+		if answer.IsCorrect {
+			label += " (correct)"
+		}
+		rows = append(rows, &model.ResultRow{Label: label, Count: answer.Count})
+	}
+	return rows
+}
+
+func optionName(content *model.Content, optionId uint) string {
+	if option := findOption(content.Options, optionId); option != nil {
+		return option.Name
+	}
+	return "#" + utils.Uint2Str(optionId)
+}
+
+var resultsHeader = []string{"slide", "title", "type", "option", "measure", "count", "percentage"}
+
+// WriteResultsCSV writes the results as CSV, a line per option of every
+// slide. Texts typed by the audience are escaped so that spreadsheets do not
+// run them as formulas
+func WriteResultsCSV(w io.Writer, results *model.PresResults) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(resultsHeader); err != nil {
+		return err
+	}
+
+	for _, slide := range results.Slides {
+		for _, row := range slide.Rows {
+			err := cw.Write([]string{
+				strconv.Itoa(slide.Number),
+				csvCell(slide.Title),
+				slide.TypeName,
+				csvCell(row.Label),
+				slide.Measure,
+				utils.Uint2Str(row.Count),
+				strconv.FormatFloat(row.Percentage, 'f', 1, 64),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell escapes a text which spreadsheets would read as a formula
+func csvCell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// WriteResultsXLSX writes the results as a spreadsheet, a summary sheet
+// listing the slides followed by a sheet per slide
+func WriteResultsXLSX(w io.Writer, results *model.PresResults) error {
+	summary := &utils.Sheet{
+		Name: "Summary",
+		Rows: [][]any{{"slide", "title", "type", "measure", "total", "top option"}},
+	}
+	sheets := []*utils.Sheet{summary}
+
+	for _, slide := range results.Slides {
+		number := slide.Number
+		summary.Rows = append(summary.Rows, []any{number, slide.Title, slide.TypeName, slide.Measure, slide.Total, topOption(slide)})
+
+		sheet := &utils.Sheet{
+			Name: fmt.Sprintf("%d %s", number, slide.Title),
+			Rows: [][]any{
+				{"slide", number},
+				{"title", slide.Title},
+				{"type", slide.TypeName},
+				{},
+				{"option", slide.Measure, "percentage"},
+			},
+		}
+		for _, row := range slide.Rows {
+			sheet.Rows = append(sheet.Rows, []any{row.Label, row.Count, row.Percentage})
+		}
+		sheet.Rows = append(sheet.Rows, []any{"total", slide.Total})
+		sheets = append(sheets, sheet)
+	}
+
+	return utils.WriteXLSX(w, sheets)
+}
+
+// topOption is the label of the option counted the most, the first one on a
+// tie, empty when the slide got no responses
+func topOption(slide *model.SlideResults) string {
+	var top *model.ResultRow
+	for _, row := range slide.Rows {
+		if row.Count > 0 && (top == nil || row.Count > top.Count) {
+			top = row
+		}
+	}
+	if top == nil {
+		return ""
+	}
+	return top.Label
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"database/sql"
+	"errors"
+)
+
+const maxScaleSpan = 100
+
+var (
+	ErrInvalidScale  = errors.New("scale range is invalid")
+	ErrInvalidRating = errors.New("ratings must rate statements of the slide within its range")
+)
+
+func defaultScale() *model.Scale {
+	return &model.Scale{MinValue: 1, MaxValue: 5}
+}
+
+func validateScale(scale *model.Scale) error {
+	if scale.MinValue >= scale.MaxValue || scale.MaxValue-scale.MinValue > maxScaleSpan {
+		return ErrInvalidScale
+	}
+	return nil
+}
+
+// createScale stores the rating range of a scales slide, 1 to 5 unless
+// specified
+func (svc *slideService) createScale(contentId string, content *model.Content) error {
+	if err := svc.slideRepo.InsertOption(contentId, content.Options); err != nil {
+		return err
+	}
+	scale := content.Scale
+	if scale == nil {
+		scale = defaultScale()
+	}
+
+	return svc.slideRepo.InsertScale(contentId, scale)
+}
+
+func (svc *slideService) updateScale(contentId string, content *model.Content) error {
+	if _, err := svc.slideRepo.UpdateOptions(contentId, content.Options); err != nil {
+		return err
+	}
+	if content.Scale == nil {
+		return nil
+	}
+
+	_, err := svc.slideRepo.UpdateScale(contentId, content.Scale)
+	return err
+}
+
+// SubmitRatings stores the ratings of a participant on the statements of a
+// scales slide. Rating again replaces the previous ratings, unless the slide
+// does not allow changing votes
+func (svc *slideService) SubmitRatings(contentId, participantId string, ratings []*model.Rating) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	if len(ratings) == 0 {
+		return ErrInvalidRating
+	}
+
+	scale, err := svc.findScale(contentId)
+	if err != nil {
+		return err
+	}
+
+	statements, err := svc.slideRepo.FindOptionIds(contentId)
+	if err != nil {
+		return err
+	}
+	isStatement := make(map[uint]bool, len(statements))
+	for _, id := range statements {
+		isStatement[id] = true
+	}
+
+	rated := make(map[uint]bool, len(ratings))
+	for _, rating := range ratings {
+		if rating == nil || !isStatement[rating.OptionId] || rated[rating.OptionId] {
+			return ErrInvalidRating
+		}
+		if rating.Value < scale.MinValue || rating.Value > scale.MaxValue {
+			return ErrInvalidRating
+		}
+		rated[rating.OptionId] = true
+	}
+
+	// The ratings of a response are stored all together or not at all. The
+	// content is locked first so that two responses of a participant cannot
+	// both pass the check for an earlier one
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Slide.LockContent(contentId); err != nil {
+			return err
+		}
+
+		rated, err := tx.Slide.HasRatings(svc.sessionId(), contentId, participantId)
+		if err != nil {
+			return err
+		}
+		if rated {
+			allowChange, err := tx.Slide.FindAllowVoteChange(contentId)
+			if err != nil {
+				return err
+			}
+			if !allowChange {
+				return ErrAlreadyVoted
+			}
+		}
+
+		return tx.Slide.UpsertRatings(svc.sessionId(), contentId, participantId, ratings)
+	})
+}
+
+// findScale returns the range of a scales slide, falling back to the default
+// one for slides created without it
+func (svc *slideService) findScale(contentId string) (*model.Scale, error) {
+	scale, err := svc.slideRepo.FindScale(contentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultScale(), nil
+	}
+	return scale, err
+}
+
+// loadScaleResults computes the average, count and histogram of the ratings
+// of every statement of a scales slide
+func (svc *slideService) loadScaleResults(contentId string, content *model.Content) error {
+	scale, err := svc.findScale(contentId)
+	if err != nil {
+		return err
+	}
+
+	counts, err := svc.slideRepo.FindRatingCounts(svc.sessionId(), contentId)
+	if err != nil {
+		return err
+	}
+
+	content.Scale = scale
+	span := scale.MaxValue - scale.MinValue + 1
+	results := make(map[uint]*model.ScaleResult, len(content.Options))
+	content.ScaleResults = nil
+	for _, statement := range content.Options {
+		result := &model.ScaleResult{OptionId: statement.Id, Histogram: make([]uint, span)}
+		results[statement.Id] = result
+		content.ScaleResults = append(content.ScaleResults, result)
+	}
+
+	sums := make(map[uint]int, len(results))
+	for _, count := range counts {
+		result, ok := results[count.OptionId]
+		index := count.Value - scale.MinValue
+		if !ok || index < 0 || index >= span {
+			// Ratings left out of range by a later change of the scale
+			continue
+		}
+		result.Histogram[index] += count.Count
+		result.Count += count.Count
+		sums[count.OptionId] += count.Value * int(count.Count)
+	}
+
+	for id, result := range results {
+		if result.Count > 0 {
+			result.Average = float64(sums[id]) / float64(result.Count)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrNoSession       = errors.New("presentation is not being presented")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+type ISessionService interface {
+	StartSession(presId, groupId string) (*model.Session, error)
+	EndSession(presId string) error
+	EndStaleSessions() (int64, error)
+	GetSessions(presId string) ([]*model.Session, error)
+	GetSession(presId, sessionId string) (*model.Session, error)
+	GetCurrentSession(presId string) (*model.Session, error)
+	ResolveSession(presId, sessionId string) (uint, error)
+}
+
+type sessionService struct {
+	sessionRepo repository.ISessionRepo
+	uow         repository.IUnitOfWork
+}
+
+func NewSessionService(sessionRepo repository.ISessionRepo, uow repository.IUnitOfWork) *sessionService {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		uow:         uow,
+	}
+}
+
+// StartSession starts a new run of a presentation, presented to a group when
+// groupId is given. A session left running, such as by a presenter who never
+// ended it, is ended first
+func (svc *sessionService) StartSession(presId, groupId string) (*model.Session, error) {
+	session := &model.Session{
+		PresentationId: utils.Str2Uint(presId),
+		GroupId:        utils.Str2Uint(groupId),
+	}
+
+	err := svc.uow.Do(func(tx *repository.Tx) error {
+		if _, err := tx.Session.EndSessions(presId); err != nil {
+			return err
+		}
+		return tx.Session.InsertSession(session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (svc *sessionService) EndSession(presId string) error {
+	_, err := svc.sessionRepo.EndSessions(presId)
+	return err
+}
+
+// EndStaleSessions ends the sessions left running by a previous run of the
+// server. Rooms live in memory only, so these sessions have no room left to
+// take responses in
+func (svc *sessionService) EndStaleSessions() (int64, error) {
+	return svc.sessionRepo.EndAllSessions()
+}
+
+// GetSessions lists the past and running sessions of a presentation, latest
+// first
+func (svc *sessionService) GetSessions(presId string) ([]*model.Session, error) {
+	return svc.sessionRepo.FindSessions(presId)
+}
+
+func (svc *sessionService) GetSession(presId, sessionId string) (*model.Session, error) {
+	session, err := svc.sessionRepo.FindSessionById(presId, sessionId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	return session, err
+}
+
+// GetCurrentSession returns the running session of a presentation, or
+// ErrNoSession when it is not being presented
+func (svc *sessionService) GetCurrentSession(presId string) (*model.Session, error) {
+	session, err := svc.sessionRepo.FindLatestSession(presId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSession
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.EndedAt != nil {
+		return nil, ErrNoSession
+	}
+
+	return session, nil
+}
+
+// ResolveSession picks the session whose results are read: the given one,
+// which must belong to the presentation, or the latest one when sessionId is
+// empty. It is zero for a presentation never presented
+func (svc *sessionService) ResolveSession(presId, sessionId string) (uint, error) {
+	if sessionId != "" {
+		session, err := svc.GetSession(presId, sessionId)
+		if err != nil {
+			return 0, err
+		}
+		return session.Id, nil
+	}
+
+	session, err := svc.sessionRepo.FindLatestSession(presId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return session.Id, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"advanced-webapp-project/repository"
+	"errors"
+)
+
+var ErrInvalidOrder = errors.New("order must list slides of the presentation at most once")
+
+// ReorderSlides moves the given slides of a presentation into the given order
+// and returns the ids of all its slides in their new order. The order may be
+// partial, the given slides are then shuffled within the positions they take
+// while the others stay in place
+func (svc *slideService) ReorderSlides(presId string, slideIds []uint) ([]uint, error) {
+	var order []uint
+	err := svc.uow.Do(func(tx *repository.Tx) error {
+		current, err := tx.Slide.FindSlideIds(presId)
+		if err != nil {
+			return err
+		}
+		if order, err = reorder(current, slideIds); err != nil {
+			return err
+		}
+		return tx.Slide.UpdateSlidePositions(presId, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func reorder(current, slideIds []uint) ([]uint, error) {
+	if len(slideIds) == 0 {
+		return nil, ErrInvalidOrder
+	}
+
+	isCurrent := make(map[uint]bool, len(current))
+	for _, id := range current {
+		isCurrent[id] = true
+	}
+	moved := make(map[uint]bool, len(slideIds))
+	for _, id := range slideIds {
+		if !isCurrent[id] || moved[id] {
+			return nil, ErrInvalidOrder
+		}
+		moved[id] = true
+	}
+
+	order := make([]uint, 0, len(current))
+	next := 0
+	for _, id := range current {
+		if moved[id] {
+			id = slideIds[next]
+			next++
+		}
+		order = append(order, id)
+	}
+	return order, nil
+}
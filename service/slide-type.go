@@ -0,0 +1,518 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownSlideType = errors.New("unsupported slide type")
+	ErrInvalidContent   = errors.New("content is missing the fields of its slide type")
+)
+
+// slideType tells how the content of the slides of a type is validated,
+// stored, built from the sub-content rows read along with it, how its
+// results are aggregated and counted per option in exports. Steps left nil
+// have nothing to do for the type
+type slideType struct {
+	// JSON schema of the content of the slides
+	schema map[string]any
+
+	validate func(content *model.Content) error
+	create   func(svc *slideService, contentId string, content *model.Content) error
+	update   func(svc *slideService, contentId string, content *model.Content) error
+	load     func(content *model.Content, row *model.SubContent)
+	results  func(svc *slideService, contentId string, slide *model.Slide) error
+	rows     func(content *model.Content) []*model.ResultRow
+
+	// What the counts of the rows are, such as votes or points
+	measure string
+}
+
+// slideTypes registers every supported slide type by id. Adding a slide type
+// takes an entry here and its id in `question_types`
+var slideTypes = map[uint]*slideType{
+	model.SlideTypeMultipleChoice: {
+		schema:   contentSchema(map[string]any{"options": optionsSchema(optionSchema)}),
+		validate: validateOptions,
+		create:   createOptions,
+		update:   updateOptions,
+		load:     loadOption,
+		rows:     optionRows,
+		measure:  "votes",
+	},
+	model.SlideTypeWordCloud: {
+		schema: contentSchema(nil),
+		results: func(svc *slideService, contentId string, slide *model.Slide) (err error) {
+			slide.Content.Words, err = svc.slideRepo.FindWordFrequencies(svc.sessionId(), contentId)
+			return err
+		},
+		rows:    wordRows,
+		measure: "mentions",
+	},
+	model.SlideTypeOpenEnded: {
+		schema: contentSchema(nil),
+		results: func(svc *slideService, contentId string, slide *model.Slide) (err error) {
+			slide.Content.Answers, err = svc.slideRepo.FindAnswers(svc.sessionId(), contentId)
+			return err
+		},
+	},
+	model.SlideTypeScales: {
+		schema: contentSchema(map[string]any{
+			"options": optionsSchema(optionSchema),
+			"scale": objectSchema(nil, map[string]any{
+				"min_value": typeSchema("integer"),
+				"max_value": typeSchema("integer"),
+				"min_label": typeSchema("string"),
+				"max_label": typeSchema("string"),
+			}),
+		}),
+		validate: func(content *model.Content) error {
+			if err := validateOptions(content); err != nil || content.Scale == nil {
+				return err
+			}
+			return validateScale(content.Scale)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.createScale(contentId, content)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.updateScale(contentId, content)
+		},
+		load: loadOption,
+		results: func(svc *slideService, contentId string, slide *model.Slide) error {
+			return svc.loadScaleResults(contentId, slide.Content)
+		},
+		rows:    scaleRows,
+		measure: "ratings",
+	},
+	model.SlideTypeRanking: {
+		schema:   contentSchema(map[string]any{"options": optionsSchema(optionSchema)}),
+		validate: validateOptions,
+		create:   createOptions,
+		update:   updateOptions,
+		load:     loadOption,
+		results: func(svc *slideService, contentId string, slide *model.Slide) error {
+			return svc.loadRankingResults(contentId, slide.Content)
+		},
+		rows:    rankingRows,
+		measure: "Borda score",
+	},
+	model.SlideTypeSelectAnswer: {
+		schema: contentSchema(map[string]any{
+			"options":    optionsSchema(quizOptionSchema),
+			"time_limit": timeLimitSchema,
+		}, "options"),
+		validate: func(content *model.Content) error {
+			return validateQuizContent(model.SlideTypeSelectAnswer, content)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.createQuiz(contentId, model.SlideTypeSelectAnswer, content)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.updateQuiz(contentId, model.SlideTypeSelectAnswer, content)
+		},
+		load: loadOption,
+	},
+	model.SlideTypeTypeAnswer: {
+		schema: contentSchema(map[string]any{
+			"options":    optionsSchema(optionSchema),
+			"time_limit": timeLimitSchema,
+			"answer_policy": objectSchema([]string{"policy"}, map[string]any{
+				"policy": map[string]any{
+					"type": "string",
+					"enum": []string{AnswerPolicyExact, AnswerPolicyNormalized, AnswerPolicyFuzzy},
+				},
+				"max_distance": map[string]any{"type": "integer", "minimum": 0, "maximum": maxMaxDistance},
+			}),
+		}, "options"),
+		validate: func(content *model.Content) error {
+			return validateQuizContent(model.SlideTypeTypeAnswer, content)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.createQuiz(contentId, model.SlideTypeTypeAnswer, content)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.updateQuiz(contentId, model.SlideTypeTypeAnswer, content)
+		},
+		load: loadOption,
+		results: func(svc *slideService, contentId string, slide *model.Slide) (err error) {
+			slide.Content.AnswerPolicy, err = svc.findAnswerPolicy(contentId)
+			return err
+		},
+	},
+	model.SlideTypeHeading: {
+		schema: contentSchema(map[string]any{
+			"heading": objectSchema(nil, map[string]any{
+				"heading":     typeSchema("string"),
+				"sub_heading": typeSchema("string"),
+				"image":       typeSchema("string"),
+			}),
+		}, "heading"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Heading != nil)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertHeading(contentId, content.Heading)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateHeading(contentId, content.Heading)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Heading = &model.Heading{Id: row.Id, Heading: row.Heading, SubHeading: row.SubHeading, Image: row.Image}
+		},
+	},
+	model.SlideTypeParagraph: {
+		schema: contentSchema(map[string]any{
+			"paragraph": objectSchema(nil, map[string]any{
+				"heading": typeSchema("string"),
+				"text":    typeSchema("string"),
+				"image":   typeSchema("string"),
+			}),
+		}, "paragraph"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Paragraph != nil)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertParagraph(contentId, content.Paragraph)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateParagraph(contentId, content.Paragraph)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Paragraph = &model.Paragraph{Id: row.Id, Heading: row.Heading, Text: row.SubHeading, Image: row.Image}
+		},
+	},
+	model.SlideTypeBullets: {
+		schema: contentSchema(map[string]any{
+			"bullets": objectSchema([]string{"items"}, map[string]any{
+				"heading": typeSchema("string"),
+				"items":   map[string]any{"type": "array", "items": typeSchema("string")},
+				"image":   typeSchema("string"),
+			}),
+		}, "bullets"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Bullets != nil && len(content.Bullets.Items) > 0)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertBullets(contentId, content.Bullets)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateBullets(contentId, content.Bullets)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			// Items are stored as a JSON array
+			var items []string
+			_ = json.Unmarshal([]byte(row.SubHeading), &items)
+			content.Bullets = &model.Bullets{Id: row.Id, Heading: row.Heading, Items: items, Image: row.Image}
+		},
+	},
+	model.SlideTypeImage: {
+		schema: contentSchema(map[string]any{
+			"image_slide": objectSchema([]string{"url"}, map[string]any{
+				"heading": typeSchema("string"),
+				"url":     typeSchema("string"),
+				"caption": typeSchema("string"),
+			}),
+		}, "image_slide"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.ImageSlide != nil && !isBlank(content.ImageSlide.Url))
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertImage(contentId, content.ImageSlide)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateImage(contentId, content.ImageSlide)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.ImageSlide = &model.Image{Id: row.Id, Heading: row.Heading, Caption: row.SubHeading, Url: row.Image}
+		},
+	},
+	model.SlideTypeVideo: {
+		schema: contentSchema(map[string]any{
+			"video": objectSchema([]string{"url"}, map[string]any{
+				"heading":    typeSchema("string"),
+				"url":        typeSchema("string"),
+				"start_time": map[string]any{"type": "integer", "minimum": 0},
+			}),
+		}, "video"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Video != nil && !isBlank(content.Video.Url))
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertVideo(contentId, content.Video)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateVideo(contentId, content.Video)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Video = &model.Video{Id: row.Id, Heading: row.Heading, StartTime: utils.Str2Uint(row.SubHeading), Url: row.Image}
+		},
+	},
+	model.SlideTypeBig: {
+		schema: contentSchema(map[string]any{
+			"big": objectSchema([]string{"text"}, map[string]any{
+				"text":     typeSchema("string"),
+				"sub_text": typeSchema("string"),
+			}),
+		}, "big"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Big != nil && !isBlank(content.Big.Text))
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertBig(contentId, content.Big)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateBig(contentId, content.Big)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Big = &model.Big{Id: row.Id, Text: row.Heading, SubText: row.SubHeading}
+		},
+	},
+	model.SlideTypeQuote: {
+		schema: contentSchema(map[string]any{
+			"quote": objectSchema([]string{"quote"}, map[string]any{
+				"quote":  typeSchema("string"),
+				"author": typeSchema("string"),
+				"image":  typeSchema("string"),
+			}),
+		}, "quote"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Quote != nil && !isBlank(content.Quote.Quote))
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertQuote(contentId, content.Quote)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateQuote(contentId, content.Quote)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Quote = &model.Quote{Id: row.Id, Quote: row.Heading, Author: row.SubHeading, Image: row.Image}
+		},
+	},
+	model.SlideTypeNumber: {
+		schema: contentSchema(map[string]any{
+			"number": objectSchema([]string{"number"}, map[string]any{
+				"number":  typeSchema("string"),
+				"caption": typeSchema("string"),
+			}),
+		}, "number"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Number != nil && !isBlank(content.Number.Number))
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertNumber(contentId, content.Number)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateNumber(contentId, content.Number)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Number = &model.Number{Id: row.Id, Number: row.Heading, Caption: row.SubHeading}
+		},
+	},
+	model.SlideTypeInstructions: {
+		schema: contentSchema(map[string]any{
+			"instructions": objectSchema(nil, map[string]any{
+				"heading": typeSchema("string"),
+				"text":    typeSchema("string"),
+			}),
+		}, "instructions"),
+		validate: func(content *model.Content) error {
+			return requireContent(content.Instructions != nil)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.slideRepo.InsertInstructions(contentId, content.Instructions)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			_, err := svc.slideRepo.UpdateInstructions(contentId, content.Instructions)
+			return err
+		},
+		load: func(content *model.Content, row *model.SubContent) {
+			content.Instructions = &model.Instructions{Id: row.Id, Heading: row.Heading, Text: row.SubHeading}
+		},
+	},
+	model.SlideType100Points: {
+		schema:   contentSchema(map[string]any{"options": optionsSchema(optionSchema)}),
+		validate: validateOptions,
+		create:   createOptions,
+		update:   updateOptions,
+		load:     loadOption,
+		results: func(svc *slideService, contentId string, slide *model.Slide) error {
+			return svc.loadPointsResults(contentId, slide.Content)
+		},
+		rows:    pointsRows,
+		measure: "points",
+	},
+	model.SlideType2x2Grid: {
+		schema: contentSchema(map[string]any{
+			"options": optionsSchema(optionSchema),
+			"grid_axes": objectSchema(nil, map[string]any{
+				"x_label": typeSchema("string"),
+				"x_min":   typeSchema("integer"),
+				"x_max":   typeSchema("integer"),
+				"y_label": typeSchema("string"),
+				"y_min":   typeSchema("integer"),
+				"y_max":   typeSchema("integer"),
+			}),
+		}),
+		validate: func(content *model.Content) error {
+			if err := validateOptions(content); err != nil || content.GridAxes == nil {
+				return err
+			}
+			return validateGridAxes(content.GridAxes)
+		},
+		create: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.createGrid(contentId, content)
+		},
+		update: func(svc *slideService, contentId string, content *model.Content) error {
+			return svc.updateGrid(contentId, content)
+		},
+		load: loadOption,
+		results: func(svc *slideService, contentId string, slide *model.Slide) error {
+			return svc.loadGridResults(contentId, slide.Content)
+		},
+		rows:    gridRows,
+		measure: "placements",
+	},
+	model.SlideTypeWhoWillWin: {
+		schema:   contentSchema(map[string]any{"options": optionsSchema(optionSchema)}),
+		validate: validateOptions,
+		create:   createOptions,
+		update:   updateOptions,
+		load:     loadOption,
+		results: func(svc *slideService, contentId string, slide *model.Slide) (err error) {
+			slide.Content.Prediction, err = svc.findPredictionResult(contentId)
+			return err
+		},
+		rows:    optionRows,
+		measure: "predictions",
+	},
+	model.SlideTypePinOnImage: {
+		// The pins are dropped on the image of the content
+		schema: contentSchema(nil, "image"),
+		validate: func(content *model.Content) error {
+			return requireContent(!isBlank(content.Image))
+		},
+		results: func(svc *slideService, contentId string, slide *model.Slide) (err error) {
+			slide.Content.Heatmap, err = svc.GetHeatmap(slide, DefaultHeatmapResolution)
+			return err
+		},
+		rows:    pinRows,
+		measure: "pins",
+	},
+}
+
+// GetSlideTypes lists the catalogue of slide types along with the schema of
+// the supported ones
+func (svc *slideService) GetSlideTypes() ([]*model.SlideCategory, error) {
+	categories, err := svc.slideRepo.FindSlideTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, category := range categories {
+		for _, t := range category.Types {
+			if registered, ok := slideTypes[t.Id]; ok {
+				t.Schema = registered.schema
+			}
+		}
+	}
+
+	return categories, nil
+}
+
+func createOptions(svc *slideService, contentId string, content *model.Content) error {
+	return svc.slideRepo.InsertOption(contentId, content.Options)
+}
+
+func updateOptions(svc *slideService, contentId string, content *model.Content) error {
+	_, err := svc.slideRepo.UpdateOptions(contentId, content.Options)
+	return err
+}
+
+func loadOption(content *model.Content, row *model.SubContent) {
+	content.Options = append(content.Options, &model.Option{
+		Id:         row.Id,
+		Name:       row.Heading,
+		Image:      row.Image,
+		TotalVotes: row.TotalVotes,
+		IsCorrect:  row.IsCorrect,
+	})
+}
+
+// validateOptions checks that every option is named, as the schema of the
+// options requires
+func validateOptions(content *model.Content) error {
+	for _, option := range content.Options {
+		if option == nil || isBlank(option.Name) {
+			return ErrInvalidContent
+		}
+	}
+	return nil
+}
+
+func requireContent(ok bool) error {
+	if !ok {
+		return ErrInvalidContent
+	}
+	return nil
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+var (
+	optionSchema = objectSchema([]string{"name"}, map[string]any{
+		"name":  typeSchema("string"),
+		"image": typeSchema("string"),
+	})
+	quizOptionSchema = objectSchema([]string{"name"}, map[string]any{
+		"name":       typeSchema("string"),
+		"image":      typeSchema("string"),
+		"is_correct": typeSchema("boolean"),
+	})
+	timeLimitSchema = map[string]any{"type": "integer", "minimum": 0, "maximum": MaxQuizTimeLimit}
+)
+
+// contentSchema is the schema of the content of a slide, the fields shared by
+// every slide type along with the given ones
+func contentSchema(properties map[string]any, required ...string) map[string]any {
+	all := map[string]any{
+		"title":             typeSchema("string"),
+		"meta":              typeSchema("string"),
+		"image":             typeSchema("string"),
+		"allow_vote_change": typeSchema("boolean"),
+	}
+	for name, property := range properties {
+		all[name] = property
+	}
+	return objectSchema(required, all)
+}
+
+func objectSchema(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func optionsSchema(option map[string]any) map[string]any {
+	return map[string]any{"type": "array", "items": option}
+}
+
+func typeSchema(name string) map[string]any {
+	return map[string]any{"type": name}
+}
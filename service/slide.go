@@ -3,85 +3,289 @@ package service
 import (
 	"advanced-webapp-project/model"
 	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrOptionNotFound = errors.New("option not found")
+	ErrAlreadyVoted   = errors.New("participant already voted on this slide")
 )
 
 type ISlideService interface {
+	InSession(sessionId uint) ISlideService
 	GetAllSlides(presId string) ([]*model.Slide, error)
 	GetSlideById(slideId string) (*model.Slide, error)
-	CreateSlide(slide *model.Slide) error
-	CreateContent(slideId string, content *model.Content) error
-	CreateOption(contentId string, options []*model.Option) error
-	CreateHeading(contentId string, heading *model.Heading) error
-	CreateParagraph(contentId string, paragraph *model.Paragraph) error
-	UpdateSlide(presId string, slide model.Slide) (int64, error)
-	UpdateContent(slideId string, content model.Content) (int64, error)
-	UpdateOptions(contentId string, options []*model.Option) (int64, error)
-	UpdateHeading(contentId string, heading *model.Heading) (int64, error)
-	UpdateParagraph(contentId string, paragraph *model.Paragraph) (int64, error)
-	UpdateOptionVote(contentId string, optionId string) (int64, error)
+	ValidateSlide(slide *model.Slide) error
+	CreateSlide(slide *model.Slide, index int) error
+	ReorderSlides(presId string, slideIds []uint) ([]uint, error)
+	UpdateSlide(presId, contentId string, slide *model.Slide) error
+	GetSlideTypes() ([]*model.SlideCategory, error)
+	SubmitVote(contentId, optionId, participantId string) error
+	SubmitWord(contentId, participantId, word string) error
+	SubmitAnswer(contentId, participantId, author, text string) (*model.OpenEndedAnswer, error)
+	ModerateAnswer(contentId, answerId string, moderation model.AnswerModeration) (*model.OpenEndedAnswer, error)
+	SubmitRatings(contentId, participantId string, ratings []*model.Rating) error
+	SubmitRanking(contentId, participantId string, ranking []uint) error
+	SubmitAllocation(contentId, participantId string, allocations []*model.Allocation) error
+	SubmitGridPoints(contentId, participantId string, points []*model.GridPoint) error
+	SubmitPin(contentId, participantId string, pin *model.Pin) error
+	GetHeatmap(slide *model.Slide, resolution int) (*model.Heatmap, error)
+	SubmitPrediction(contentId, optionId, participantId string) error
+	ResolvePrediction(contentId, optionId string) (*model.PredictionResult, error)
+	DuplicateSlide(presId, slideId string) (*model.Slide, error)
+	CopyPresentation(presId, userId string) (*model.Pres, error)
+	ExportPresentation(presId string) (*model.PresDocument, error)
+	ImportPresentation(doc *model.PresDocument, userId string) (*model.Pres, error)
+	ImportMarkdown(markdown, name, userId string) (*model.Pres, error)
 	DeleteSlide(presId, slideId string) (int64, error)
 }
 
 type slideService struct {
 	slideRepo repository.ISlideRepo
+	uow       repository.IUnitOfWork
+
+	// Session the responses are submitted to and the results read from, none
+	// when zero
+	session uint
 }
 
-func NewSlideService(slideRepo repository.ISlideRepo) *slideService {
+func NewSlideService(slideRepo repository.ISlideRepo, uow repository.IUnitOfWork) *slideService {
 	return &slideService{
 		slideRepo: slideRepo,
+		uow:       uow,
 	}
 }
 
-func (svc *slideService) GetAllSlides(presId string) ([]*model.Slide, error) {
-	return svc.slideRepo.FindAllSlides(presId)
+// InSession is the service submitting responses to a session and reading the
+// results of that session only. Outside of a session, responses are refused
+// and slides come without results
+func (svc *slideService) InSession(sessionId uint) ISlideService {
+	return &slideService{
+		slideRepo: svc.slideRepo,
+		uow:       svc.uow,
+		session:   sessionId,
+	}
 }
 
-func (svc *slideService) GetSlideById(slideId string) (*model.Slide, error) {
-	return svc.slideRepo.FindSlideById(slideId)
+// within is the service running its statements in the transaction of tx
+func (svc *slideService) within(tx *repository.Tx) *slideService {
+	return &slideService{
+		slideRepo: tx.Slide,
+		uow:       svc.uow,
+		session:   svc.session,
+	}
+}
+
+func (svc *slideService) sessionId() string {
+	return utils.Uint2Str(svc.session)
 }
 
-func (svc *slideService) CreateSlide(slide *model.Slide) error {
-	return svc.slideRepo.InsertSlide(slide)
+// requireSession refuses the responses submitted outside of a session
+func (svc *slideService) requireSession() error {
+	if svc.session == 0 {
+		return ErrNoSession
+	}
+	return nil
 }
 
-func (svc *slideService) CreateContent(slideId string, content *model.Content) error {
-	return svc.slideRepo.InsertContent(slideId, content)
+func (svc *slideService) GetAllSlides(presId string) ([]*model.Slide, error) {
+	slides, err := svc.slideRepo.FindAllSlides(presId, svc.sessionId())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slide := range slides {
+		if err = svc.loadResults(slide); err != nil {
+			return nil, err
+		}
+	}
+
+	return slides, nil
 }
 
-func (svc *slideService) CreateOption(contentId string, options []*model.Option) error {
-	return svc.slideRepo.InsertOption(contentId, options)
+func (svc *slideService) GetSlideById(slideId string) (*model.Slide, error) {
+	slide, err := svc.slideRepo.FindSlideById(slideId, svc.sessionId())
+	if err != nil {
+		return nil, err
+	}
+
+	return slide, svc.loadResults(slide)
 }
 
-func (svc *slideService) CreateHeading(contentId string, heading *model.Heading) error {
-	return svc.slideRepo.InsertHeading(contentId, heading)
+// loadResults builds the sub-contents of a slide from the rows read along
+// with it, then attaches the audience results kept outside of them, as told
+// by its slide type
+func (svc *slideService) loadResults(slide *model.Slide) error {
+	t, ok := slideTypes[slide.Type]
+	if !ok {
+		return nil
+	}
+
+	if t.load != nil {
+		for _, row := range slide.Content.SubContents {
+			t.load(slide.Content, row)
+		}
+	}
+	slide.Content.SubContents = nil
+
+	if t.results == nil {
+		return nil
+	}
+	return t.results(svc, utils.Uint2Str(slide.Content.Id), slide)
 }
 
-func (svc *slideService) CreateParagraph(contentId string, paragraph *model.Paragraph) error {
-	return svc.slideRepo.InsertParagraph(contentId, paragraph)
+// ValidateSlide checks the content of a slide against its slide type, before
+// anything of the slide is stored
+func (svc *slideService) ValidateSlide(slide *model.Slide) error {
+	t, ok := slideTypes[slide.Type]
+	if !ok {
+		return ErrUnknownSlideType
+	}
+	if slide.Content == nil {
+		return ErrInvalidContent
+	}
+	if t.validate == nil {
+		return nil
+	}
+	return t.validate(slide.Content)
 }
 
-func (svc *slideService) UpdateSlide(presId string, slide model.Slide) (int64, error) {
-	return svc.slideRepo.UpdateSlide(presId, slide)
+// CreateSlide stores a slide along with its content and sub-contents in one
+// transaction, so that a failure leaves nothing of the slide behind. The
+// slide goes in at index among the slides of its presentation, or last when
+// index is out of range
+func (svc *slideService) CreateSlide(slide *model.Slide, index int) error {
+	if err := svc.ValidateSlide(slide); err != nil {
+		return err
+	}
+
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		return svc.within(tx).insertSlide(slide, index)
+	})
 }
 
-func (svc *slideService) UpdateContent(slideId string, content model.Content) (int64, error) {
-	return svc.slideRepo.UpdateContent(slideId, content)
+// insertSlide stores a slide at index among the slides of its presentation,
+// moving the slides after it down. The presentation is locked first so that
+// two insertions cannot number their slides from the same list
+func (svc *slideService) insertSlide(slide *model.Slide, index int) error {
+	presId := utils.Uint2Str(slide.PresentationId)
+	if err := svc.slideRepo.LockPresentation(presId); err != nil {
+		return err
+	}
+
+	ids, err := svc.slideRepo.FindSlideIds(presId)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index > len(ids) {
+		index = len(ids)
+	}
+	slide.Position = index
+
+	if err = svc.storeSlide(slide); err != nil {
+		return err
+	}
+
+	order := make([]uint, 0, len(ids)+1)
+	order = append(order, ids[:index]...)
+	order = append(order, slide.Id)
+	order = append(order, ids[index:]...)
+	return svc.slideRepo.UpdateSlidePositions(presId, order)
 }
 
-func (svc *slideService) UpdateOptions(contentId string, options []*model.Option) (int64, error) {
-	return svc.slideRepo.UpdateOptions(contentId, options)
+// storeSlide stores a slide at its position as is, along with its content and
+// sub-contents
+func (svc *slideService) storeSlide(slide *model.Slide) error {
+	if err := svc.slideRepo.InsertSlide(slide); err != nil {
+		return err
+	}
+
+	if err := svc.slideRepo.InsertContent(utils.Uint2Str(slide.Id), slide.Content); err != nil {
+		return err
+	}
+	t := slideTypes[slide.Type]
+	if t.create == nil {
+		return nil
+	}
+	return t.create(svc, utils.Uint2Str(slide.Content.Id), slide.Content)
 }
 
-func (svc *slideService) UpdateOptionVote(contentId string, optionId string) (int64, error) {
-	return svc.slideRepo.UpdateOptionVote(contentId, optionId)
+// UpdateSlide updates a slide along with its content and sub-contents in one
+// transaction
+func (svc *slideService) UpdateSlide(presId, contentId string, slide *model.Slide) error {
+	if err := svc.ValidateSlide(slide); err != nil {
+		return err
+	}
+	t := slideTypes[slide.Type]
+	slideId := utils.Uint2Str(slide.Id)
+
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		if _, err := tx.Slide.UpdateSlide(presId, *slide); err != nil {
+			return err
+		}
+		if _, err := tx.Slide.UpdateContent(slideId, *slide.Content); err != nil {
+			return err
+		}
+		if t.update == nil {
+			return nil
+		}
+		return t.update(svc.within(tx), contentId, slide.Content)
+	})
 }
 
-func (svc *slideService) UpdateHeading(contentId string, heading *model.Heading) (int64, error) {
-	return svc.slideRepo.UpdateHeading(contentId, heading)
+// SubmitVote records the vote of a participant on a multiple choice slide.
+// Each participant holds at most one vote per slide, which can only be moved
+// to another option when the slide allows vote changes
+func (svc *slideService) SubmitVote(contentId, optionId, participantId string) error {
+	return svc.vote(model.SlideTypeMultipleChoice, contentId, optionId, participantId)
 }
 
-func (svc *slideService) UpdateParagraph(contentId string, paragraph *model.Paragraph) (int64, error) {
-	return svc.slideRepo.UpdateParagraph(contentId, paragraph)
+// vote records the vote of a participant on an option of a slide of the given
+// type, any other slide holding no such option
+func (svc *slideService) vote(slideType uint, contentId, optionId, participantId string) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	allowChange, err := svc.slideRepo.FindVoteChangePolicy(slideType, contentId, optionId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrOptionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	vote := &model.Vote{
+		SessionId:     svc.session,
+		ContentId:     utils.Str2Uint(contentId),
+		OptionId:      utils.Str2Uint(optionId),
+		ParticipantId: participantId,
+	}
+
+	previous, err := svc.slideRepo.FindVote(svc.sessionId(), contentId, participantId)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		res, err := svc.slideRepo.InsertVote(vote)
+		if err != nil {
+			return err
+		}
+		if res == 0 {
+			// A concurrent vote of the same participant won the race
+			return ErrAlreadyVoted
+		}
+		return nil
+	case err != nil:
+		return err
+	case previous.OptionId == vote.OptionId:
+		return nil
+	case !allowChange:
+		return ErrAlreadyVoted
+	}
+
+	_, err = svc.slideRepo.UpdateVote(vote)
+	return err
 }
 
 func (svc *slideService) DeleteSlide(presId, slideId string) (int64, error) {
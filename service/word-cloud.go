@@ -0,0 +1,71 @@
+package service
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/repository"
+	"advanced-webapp-project/utils"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxWordLength          = 25
+	maxWordsPerParticipant = 3
+)
+
+var (
+	ErrInvalidWord  = errors.New("word is empty or too long")
+	ErrTooManyWords = errors.New("participant already submitted the maximum number of words")
+)
+
+// NormalizeWord folds the spellings of a word cloud entry together: case,
+// surrounding punctuation and inner whitespace are ignored
+func NormalizeWord(word string) (string, error) {
+	word = strings.ToLower(strings.Join(strings.Fields(word), " "))
+	word = strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+	})
+
+	if word == "" || utf8.RuneCountInString(word) > maxWordLength {
+		return "", ErrInvalidWord
+	}
+
+	return word, nil
+}
+
+// SubmitWord adds a word of a participant to a word cloud slide
+func (svc *slideService) SubmitWord(contentId, participantId, word string) error {
+	if err := svc.requireSession(); err != nil {
+		return err
+	}
+
+	normalized, err := NormalizeWord(word)
+	if err != nil {
+		return err
+	}
+
+	// Words are counted and added under a lock on the slide, or concurrent
+	// submissions would go past the limit
+	return svc.uow.Do(func(tx *repository.Tx) error {
+		if err := tx.Slide.LockContent(contentId); err != nil {
+			return err
+		}
+
+		count, err := tx.Slide.CountParticipantWords(svc.sessionId(), contentId, participantId)
+		if err != nil {
+			return err
+		}
+		if count >= maxWordsPerParticipant {
+			return ErrTooManyWords
+		}
+
+		return tx.Slide.InsertWord(&model.WordCloudWord{
+			SessionId:     svc.session,
+			ContentId:     utils.Str2Uint(contentId),
+			ParticipantId: participantId,
+			Word:          normalized,
+		})
+	})
+}
@@ -6,3 +6,7 @@ func Str2Uint(value string) uint {
 	res, _ := strconv.ParseUint(value, 10, 64)
 	return uint(res)
 }
+
+func Uint2Str(value uint) string {
+	return strconv.FormatUint(uint64(value), 10)
+}
@@ -0,0 +1,149 @@
+package websocket
+
+import (
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Role of a client in its room, deciding which message types it may send
+type Role string
+
+const (
+	// RolePresenter controls the presentation: the owner of the presentation,
+	// or an owner or co-owner of the group it is presented to
+	RolePresenter Role = "presenter"
+
+	// RoleMember is a member of the group the presentation is presented to
+	RoleMember Role = "member"
+
+	// RoleAudience is anyone else, logged in or not
+	RoleAudience Role = "audience"
+)
+
+// Group role ids as stored in `roles`
+const (
+	groupRoleOwner   = "1"
+	groupRoleCoOwner = "2"
+)
+
+var (
+	errInvalidToken = errors.New("invalid token")
+	errNotAMember   = errors.New("not a member of this group")
+)
+
+// Services groups the application services used while serving websocket
+// clients
+type Services struct {
+	Slide       service.ISlideService
+	Pres        service.IPresService
+	Group       service.IGroupService
+	User        service.IUserService
+	Question    service.IQuestionService
+	Message     service.IMessageService
+	Quiz        service.IQuizService
+	Session     service.ISessionService
+	JWT         service.IJWTService
+	Participant service.IParticipantService
+}
+
+// identity is who a client is, resolved once during the handshake
+type identity struct {
+	userId   string
+	username string
+	role     Role
+
+	// Who the client votes and answers as. Anonymous clients keep their
+	// signed id across reconnects by passing it back in the `participant`
+	// query parameter
+	participantId string
+
+	// Group a presenter presents the room to, the group its sessions are
+	// recorded for
+	groupId string
+}
+
+// authenticate resolves the identity of a connecting client: its role in the
+// room and the participant id it votes as
+func authenticate(svc *Services, roomId string, r *http.Request) (*identity, error) {
+	id, err := resolveRole(svc, roomId, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if id.userId != "" {
+		id.participantId = svc.Participant.ForUser(id.userId)
+		if user, err := svc.User.GetProfile(id.userId); err == nil {
+			id.username = user.Username
+		}
+	} else if participantId := r.URL.Query().Get("participant"); svc.Participant.VerifyAnonymous(participantId) {
+		id.participantId = participantId
+	} else {
+		id.participantId = svc.Participant.NewAnonymous()
+	}
+	return id, nil
+}
+
+// resolveRole authenticates the client and decides its role. The JWT is
+// optional and read from the `token` query parameter, since browsers cannot
+// set headers on websocket handshakes, or from the Authorization header.
+// Without a token the client joins as anonymous audience. When `groupId` is
+// given the room is a group presentation and only members of that group may
+// join it
+func resolveRole(svc *Services, roomId string, r *http.Request) (*identity, error) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	groupId := r.URL.Query().Get("groupId")
+
+	if token == "" {
+		if groupId != "" {
+			return nil, errNotAMember
+		}
+		return &identity{role: RoleAudience}, nil
+	}
+
+	parsed, err := svc.JWT.ValidateToken(token)
+	if err != nil || !parsed.Valid {
+		return nil, errInvalidToken
+	}
+	claims, _ := svc.JWT.ExtractToken(token)
+	userId, _ := claims["user_id"].(string)
+	if userId == "" {
+		return nil, errInvalidToken
+	}
+
+	if pres, err := svc.Pres.GetPresentationById(roomId); err == nil && utils.Uint2Str(pres.Owner.Id) == userId {
+		return &identity{userId: userId, role: RolePresenter, groupId: presentedTo(svc, roomId, groupId)}, nil
+	}
+
+	if groupId == "" {
+		return &identity{userId: userId, role: RoleAudience}, nil
+	}
+
+	groupRole, err := svc.Group.GetUserRole(groupId, userId)
+	if err != nil {
+		return nil, errNotAMember
+	}
+	if groupRole == groupRoleOwner || groupRole == groupRoleCoOwner {
+		if presentedTo(svc, roomId, groupId) != "" {
+			return &identity{userId: userId, role: RolePresenter, groupId: groupId}, nil
+		}
+	}
+	return &identity{userId: userId, role: RoleMember}, nil
+}
+
+// presentedTo is groupId when the group is being presented the room, empty
+// otherwise
+func presentedTo(svc *Services, roomId, groupId string) string {
+	if groupId == "" {
+		return ""
+	}
+	if info, err := svc.Group.GetGroupPresentationInfo(groupId); err == nil && utils.Uint2Str(info.PresId) == roomId {
+		return groupId
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package websocket
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+)
+
+// handleChat persists a chat message and relays it to the room
+func handleChat(c *Client, env *Envelope) error {
+	var payload ChatPayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	message := &model.Message{
+		UserId:         utils.Str2Uint(c.userId),
+		PresentationId: utils.Str2Uint(c.roomId),
+		Message:        payload.Message,
+		Username:       c.username,
+	}
+	if err := c.svc.Message.SendMessage(message); err != nil {
+		return translateError(err)
+	}
+
+	c.broadcast(TypeChat, message)
+	c.reply(TypeAck, env.Ref, nil)
+	return nil
+}
@@ -1,10 +1,8 @@
 package websocket
 
 import (
-	"advanced-webapp-project/service"
 	"bytes"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/gorilla/websocket"
 	"log"
 	"net/http"
@@ -22,7 +20,7 @@ const (
 	pingPeriod = (pongWait * 9) / 10
 
 	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	maxMessageSize = 4096
 )
 
 var (
@@ -33,6 +31,8 @@ var (
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  2048,
 	WriteBufferSize: 2048,
+	// Any origin may connect, what a client is allowed to do is decided by the
+	// token it presents during the handshake
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
@@ -40,15 +40,27 @@ var upgrader = websocket.Upgrader{
 
 // Client is a middleman between the websocket connection and the hub
 type Client struct {
-	slideSvc service.ISlideService
-	roomId   string
-	hub      *Hub
+	svc    *Services
+	roomId string
+	hub    *Hub
+
+	// Authenticated user id and name, empty for anonymous clients
+	userId        string
+	username      string
+	role          Role
+	participantId string
+
+	// Group the presenter presents to, empty outside of group presentations
+	groupId string
 
 	// The websocket connection
 	conn *websocket.Conn
 
 	// Buffered channel of outbound messages
 	send chan []byte
+
+	// Recent chat history, sent by the hub once the client is registered
+	backlog []byte
 }
 
 // readPump pumps messages from the websocket connection to the hub
@@ -76,24 +88,7 @@ func (c *Client) readPump() {
 			break
 		}
 		message = bytes.TrimSpace(bytes.Replace(message, newline, space, -1))
-		fmt.Printf("\n[MESSAGE]: %s typeof %T\n", message, message)
-
-		type req struct {
-			Id      string `json:"id"`
-			Option  string `json:"option"`
-			Content string `json:"content"`
-		}
-		var body req
-		if err = json.Unmarshal(message, &body); err == nil {
-			fmt.Printf("JSON: %s\n\n", body)
-			_, _ = c.slideSvc.UpdateOptionVote(body.Content, body.Option)
-		}
-
-		slide, err := c.slideSvc.GetSlideById(string(message))
-		if slide != nil {
-			message, _ = json.Marshal(*slide)
-		}
-		c.hub.broadcast <- incomingMessage{roomId: c.roomId, data: message}
+		c.dispatch(message)
 	}
 }
 
@@ -118,21 +113,17 @@ func (c *Client) writePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
 				return
 			}
-			w.Write(message)
 
-			// Add queued chat messages to the current websocket message
+			// Flush queued messages, one envelope per websocket frame so that
+			// clients can decode every frame as a single JSON document
 			n := len(c.send)
 			for i := 0; i < n; i++ {
-				w.Write(newline)
-				w.Write(<-c.send)
-			}
-
-			if err := w.Close(); err != nil {
-				return
+				if err := c.conn.WriteMessage(websocket.TextMessage, <-c.send); err != nil {
+					return
+				}
 			}
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -144,13 +135,40 @@ func (c *Client) writePump() {
 }
 
 // ServeWs handles websocket requests from the peer
-func ServeWs(slideSvc service.ISlideService, roomId string, hub *Hub, w http.ResponseWriter, r *http.Request) {
+func ServeWs(svc *Services, roomId string, hub *Hub, w http.ResponseWriter, r *http.Request) {
+	id, err := authenticate(svc, roomId, r)
+	switch {
+	case errors.Is(err, errInvalidToken):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	client := &Client{slideSvc: slideSvc, roomId: roomId, hub: hub, conn: conn, send: make(chan []byte, 256)}
+	client := &Client{
+		svc:    svc,
+		roomId: roomId,
+		hub:    hub,
+		userId: id.userId,
+		role:   id.role,
+		conn:   conn,
+		send:   make(chan []byte, 256),
+
+		username:      id.username,
+		participantId: id.participantId,
+		groupId:       id.groupId,
+	}
+	if messages, err := svc.Message.GetBacklog(roomId); err != nil {
+		log.Printf("error: %+v", err)
+	} else if client.backlog, err = encode(TypeChatHistory, "", messages); err != nil {
+		log.Printf("error: %+v", err)
+	}
 	client.hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
@@ -0,0 +1,389 @@
+package websocket
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"encoding/json"
+	"errors"
+	"log"
+)
+
+// handlerFunc handles one decoded frame sent by a client. Returning a
+// protocolError rejects the frame with that code, any other error is reported
+// to the sender as an internal error
+type handlerFunc func(c *Client, env *Envelope) error
+
+// route binds a client message type to its handler and to the roles allowed
+// to send it
+type route struct {
+	handle handlerFunc
+	roles  []Role
+}
+
+var (
+	everyone      = []Role{RolePresenter, RoleMember, RoleAudience}
+	presenterOnly = []Role{RolePresenter}
+)
+
+// routes maps each client message type to its route. Server-only types such
+// as state, results, error and ack are deliberately absent
+var routes = map[string]route{
+	TypeJoin:         {handle: handleJoin, roles: everyone},
+	TypeVote:         {handle: handleVote, roles: everyone},
+	TypeStart:        {handle: handleStart, roles: presenterOnly},
+	TypePause:        {handle: handlePause, roles: presenterOnly},
+	TypeEnd:          {handle: handleEnd, roles: presenterOnly},
+	TypeChat:         {handle: handleChat, roles: everyone},
+	TypeAsk:          {handle: handleAsk, roles: everyone},
+	TypeUpvote:       {handle: handleUpvote, roles: everyone},
+	TypeMarkAnswered: {handle: handleMarkAnswered, roles: presenterOnly},
+	TypeModerate:     {handle: handleModerate, roles: presenterOnly},
+	TypeHeatmap:      {handle: handleHeatmap, roles: presenterOnly},
+	TypeRevealWinner: {handle: handleRevealWinner, roles: presenterOnly},
+	TypeChangeSlide:  {handle: handleChangeSlide, roles: presenterOnly},
+	TypeNextSlide:    {handle: handleNextSlide, roles: presenterOnly},
+	TypePrevSlide:    {handle: handlePrevSlide, roles: presenterOnly},
+}
+
+func (r route) allows(role Role) bool {
+	return hasRole(r.roles, role)
+}
+
+// dispatch decodes a raw frame and routes it to the matching handler.
+// Malformed frames are answered with an error sent to the client only and are
+// never forwarded to the rest of the room
+func (c *Client) dispatch(raw []byte) {
+	var env Envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		c.replyError("", newProtocolError(ErrCodeMalformed, "frame is not a valid envelope"))
+		return
+	}
+
+	if env.Version != protocolVersion {
+		c.replyError(env.Ref, newProtocolError(ErrCodeVersion, "unsupported protocol version"))
+		return
+	}
+
+	route, ok := routes[env.Type]
+	if !ok {
+		c.replyError(env.Ref, newProtocolError(ErrCodeUnknownType, "unknown message type "+env.Type))
+		return
+	}
+
+	if !route.allows(c.role) {
+		c.replyError(env.Ref, newProtocolError(ErrCodeForbidden, string(c.role)+" cannot send "+env.Type))
+		return
+	}
+
+	if err := route.handle(c, &env); err != nil {
+		c.replyError(env.Ref, err)
+	}
+}
+
+func handleJoin(c *Client, env *Envelope) error {
+	c.reply(TypeAck, env.Ref, c.joinPayload())
+	return nil
+}
+
+// handleVote records the response of a participant to a slide, whose shape
+// depends on the slide type, then broadcasts the updated results to the room
+func handleVote(c *Client, env *Envelope) error {
+	var payload VotePayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+	if payload.SlideId == 0 {
+		return newProtocolError(ErrCodeBadPayload, "slide_id is required")
+	}
+
+	slides, err := c.sessionSlides()
+	if err != nil {
+		return err
+	}
+	slide, err := slides.GetSlideById(utils.Uint2Str(payload.SlideId))
+	if err != nil || utils.Uint2Str(slide.PresentationId) != c.roomId {
+		return newProtocolError(ErrCodeNotFound, "slide not found")
+	}
+	contentId := utils.Uint2Str(slide.Content.Id)
+
+	switch slide.Type {
+	case model.SlideTypeMultipleChoice:
+		if payload.OptionId == 0 {
+			return newProtocolError(ErrCodeBadPayload, "option_id is required")
+		}
+		err = slides.SubmitVote(contentId, utils.Uint2Str(payload.OptionId), c.participantId)
+	case model.SlideTypeWordCloud:
+		err = slides.SubmitWord(contentId, c.participantId, payload.Word)
+	case model.SlideTypeOpenEnded:
+		var answer *model.OpenEndedAnswer
+		answer, err = slides.SubmitAnswer(contentId, c.participantId, c.username, payload.Text)
+		if err == nil {
+			// Answers are streamed one by one rather than as whole results
+			c.broadcast(TypeAnswer, answer)
+		}
+	case model.SlideTypeScales:
+		err = slides.SubmitRatings(contentId, c.participantId, payload.Ratings)
+	case model.SlideTypeRanking:
+		err = slides.SubmitRanking(contentId, c.participantId, payload.Ranking)
+	case model.SlideType100Points:
+		err = slides.SubmitAllocation(contentId, c.participantId, payload.Allocations)
+	case model.SlideType2x2Grid:
+		err = slides.SubmitGridPoints(contentId, c.participantId, payload.GridPoints)
+	case model.SlideTypeWhoWillWin:
+		if payload.OptionId == 0 {
+			return newProtocolError(ErrCodeBadPayload, "option_id is required")
+		}
+		err = slides.SubmitPrediction(contentId, utils.Uint2Str(payload.OptionId), c.participantId)
+	case model.SlideTypePinOnImage:
+		err = slides.SubmitPin(contentId, c.participantId, payload.Pin)
+	case model.SlideTypeSelectAnswer, model.SlideTypeTypeAnswer:
+		// Quiz answers are timed against the open question of the room and
+		// acknowledged once scored
+		c.answerQuestion(env.Ref, slide, &payload)
+		return nil
+	default:
+		return newProtocolError(ErrCodeBadPayload, "slide does not accept votes")
+	}
+	if err != nil {
+		return translateError(err)
+	}
+
+	slide, err = slides.GetSlideById(utils.Uint2Str(payload.SlideId))
+	if err != nil {
+		return err
+	}
+
+	slide = publicSlide(slide)
+	c.refreshSlide(slide)
+	if slide.Type != model.SlideTypeOpenEnded {
+		c.broadcast(TypeResults, slide)
+	}
+	c.reply(TypeAck, env.Ref, nil)
+	return nil
+}
+
+// handleModerate hides or highlights an answer of an open ended slide.
+// Presenters are sent the answer as is, the rest of the room gets hidden
+// answers stripped of their text
+func handleModerate(c *Client, env *Envelope) error {
+	var payload ModeratePayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	slides, err := c.sessionSlides()
+	if err != nil {
+		return err
+	}
+	slide, err := slides.GetSlideById(utils.Uint2Str(payload.SlideId))
+	if err != nil || utils.Uint2Str(slide.PresentationId) != c.roomId {
+		return newProtocolError(ErrCodeNotFound, "slide not found")
+	}
+	if slide.Type != model.SlideTypeOpenEnded {
+		return newProtocolError(ErrCodeBadPayload, "slide has no answers to moderate")
+	}
+
+	answer, err := slides.ModerateAnswer(utils.Uint2Str(slide.Content.Id), utils.Uint2Str(payload.AnswerId), payload.AnswerModeration)
+	if err != nil {
+		return translateError(err)
+	}
+
+	c.broadcastTo(presenterOnly, TypeAnswer, answer)
+	c.broadcastTo([]Role{RoleMember, RoleAudience}, TypeAnswer, publicAnswer(answer))
+
+	if slide, err = slides.GetSlideById(utils.Uint2Str(payload.SlideId)); err == nil {
+		c.refreshSlide(publicSlide(slide))
+	}
+	c.reply(TypeAck, env.Ref, nil)
+	return nil
+}
+
+// handleRevealWinner reveals the winner of a who will win slide, then
+// broadcasts the scored outcome with the results of the slide
+func handleRevealWinner(c *Client, env *Envelope) error {
+	var payload RevealWinnerPayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	slides, err := c.sessionSlides()
+	if err != nil {
+		return err
+	}
+	slide, err := slides.GetSlideById(utils.Uint2Str(payload.SlideId))
+	if err != nil || utils.Uint2Str(slide.PresentationId) != c.roomId {
+		return newProtocolError(ErrCodeNotFound, "slide not found")
+	}
+	if slide.Type != model.SlideTypeWhoWillWin {
+		return newProtocolError(ErrCodeBadPayload, "slide has no winner to reveal")
+	}
+
+	_, err = slides.ResolvePrediction(utils.Uint2Str(slide.Content.Id), utils.Uint2Str(payload.OptionId))
+	if err != nil {
+		return translateError(err)
+	}
+
+	slide, err = slides.GetSlideById(utils.Uint2Str(payload.SlideId))
+	if err != nil {
+		return err
+	}
+
+	slide = publicSlide(slide)
+	c.refreshSlide(slide)
+	c.broadcast(TypeResults, slide)
+	c.reply(TypeAck, env.Ref, nil)
+	return nil
+}
+
+// handleHeatmap replies with the heatmap of a pin on image slide at the
+// resolution asked for. The room is streamed the default resolution with the
+// results of the slide
+func handleHeatmap(c *Client, env *Envelope) error {
+	var payload HeatmapPayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	slides, err := c.sessionSlides()
+	if err != nil {
+		return err
+	}
+	slide, err := slides.GetSlideById(utils.Uint2Str(payload.SlideId))
+	if err != nil || utils.Uint2Str(slide.PresentationId) != c.roomId {
+		return newProtocolError(ErrCodeNotFound, "slide not found")
+	}
+
+	heatmap, err := slides.GetHeatmap(slide, payload.Resolution)
+	if err != nil {
+		return translateError(err)
+	}
+
+	c.reply(TypeHeatmap, env.Ref, heatmap)
+	return nil
+}
+
+// publicAnswer is an answer as the audience may see it
+func publicAnswer(answer *model.OpenEndedAnswer) *model.OpenEndedAnswer {
+	if !answer.IsHidden {
+		return answer
+	}
+	return &model.OpenEndedAnswer{Id: answer.Id, ContentId: answer.ContentId, IsHidden: true}
+}
+
+// publicSlide is a slide as the audience may see it, without the answers
+// hidden by the presenter nor the correct answers of quiz questions
+func publicSlide(slide *model.Slide) *model.Slide {
+	if service.IsQuiz(slide.Type) {
+		return publicQuiz(slide)
+	}
+	if len(slide.Content.Answers) == 0 {
+		return slide
+	}
+
+	content := *slide.Content
+	content.Answers = nil
+	for _, answer := range slide.Content.Answers {
+		if !answer.IsHidden {
+			content.Answers = append(content.Answers, answer)
+		}
+	}
+
+	public := *slide
+	public.Content = &content
+	return &public
+}
+
+// serviceErrors maps the errors of the services caused by the content of a
+// frame to the code they are reported with
+var serviceErrors = []struct {
+	err  error
+	code string
+}{
+	{service.ErrOptionNotFound, ErrCodeNotFound},
+	{service.ErrAlreadyVoted, ErrCodeConflict},
+	{service.ErrInvalidWord, ErrCodeBadPayload},
+	{service.ErrTooManyWords, ErrCodeConflict},
+	{service.ErrInvalidAnswer, ErrCodeBadPayload},
+	{service.ErrAnswerNotFound, ErrCodeNotFound},
+	{service.ErrInvalidRating, ErrCodeBadPayload},
+	{service.ErrInvalidRanking, ErrCodeBadPayload},
+	{service.ErrInvalidAllocation, ErrCodeBadPayload},
+	{service.ErrInvalidGridPoint, ErrCodeBadPayload},
+	{service.ErrInvalidPin, ErrCodeBadPayload},
+	{service.ErrInvalidResolution, ErrCodeBadPayload},
+	{service.ErrNotPinOnImage, ErrCodeBadPayload},
+	{service.ErrPredictionResolved, ErrCodeConflict},
+	{service.ErrInvalidQuestion, ErrCodeBadPayload},
+	{service.ErrInvalidMessage, ErrCodeBadPayload},
+	{service.ErrQuestionNotFound, ErrCodeNotFound},
+	{service.ErrAlreadyUpvoted, ErrCodeConflict},
+	{service.ErrInvalidQuizAnswer, ErrCodeBadPayload},
+	{service.ErrAlreadyAnswered, ErrCodeConflict},
+	{service.ErrNoSession, ErrCodeForbidden},
+}
+
+// translateError turns a known service error into a protocolError, leaving
+// unexpected errors to be reported as internal ones
+func translateError(err error) error {
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			return newProtocolError(known.code, known.err.Error())
+		}
+	}
+	return err
+}
+
+// sessionSlides is the slide service recording responses in and reading
+// results of the session running in the room
+func (c *Client) sessionSlides() (service.ISlideService, error) {
+	sessionId := c.roomSession()
+	if sessionId == 0 {
+		return nil, newProtocolError(ErrCodeForbidden, "presentation is not running")
+	}
+	return c.svc.Slide.InSession(sessionId), nil
+}
+
+func (c *Client) joinPayload() JoinPayload {
+	return JoinPayload{RoomId: c.roomId, UserId: c.userId, Role: c.role, ParticipantId: c.participantId}
+}
+
+// reply sends a frame to this client only
+func (c *Client) reply(msgType, ref string, payload any) {
+	data, err := encode(msgType, ref, payload)
+	if err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	c.hub.broadcast <- incomingMessage{roomId: c.roomId, data: data, target: c}
+}
+
+// broadcast sends a frame to every client of this client's room
+func (c *Client) broadcast(msgType string, payload any) {
+	data, err := encode(msgType, "", payload)
+	if err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	c.hub.broadcast <- incomingMessage{roomId: c.roomId, data: data}
+}
+
+// broadcastTo sends a frame to the clients of this client's room having one
+// of the given roles
+func (c *Client) broadcastTo(roles []Role, msgType string, payload any) {
+	data, err := encode(msgType, "", payload)
+	if err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	c.hub.broadcast <- incomingMessage{roomId: c.roomId, data: data, roles: roles}
+}
+
+func (c *Client) replyError(ref string, err error) {
+	data, err := encodeError(ref, err)
+	if err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	c.hub.broadcast <- incomingMessage{roomId: c.roomId, data: data, target: c}
+}
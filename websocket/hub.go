@@ -1,15 +1,33 @@
 package websocket
 
+import "log"
+
 type incomingMessage struct {
 	roomId string
 	data   []byte
+
+	// When set, the message is delivered to this client only instead of the
+	// whole room
+	target *Client
+
+	// When set, the message is delivered to the clients of the room having
+	// one of these roles only
+	roles []Role
 }
 
 // Hub maintains the set of active clients and broadcasts messages to the clients
 type Hub struct {
+	// Services used by the hub to react to state changes on its own, such as
+	// announcing the leaderboard when a quiz question closes
+	svc *Services
+
 	// Registered clients by room
 	rooms map[string]map[*Client]bool
 
+	// Live presentation state by room. A state outlives the clients of its
+	// room until the presentation is ended, so a presenter can reconnect
+	states map[string]*RoomState
+
 	// Inbound messages from the clients
 	broadcast chan incomingMessage
 
@@ -18,14 +36,26 @@ type Hub struct {
 
 	// Unregister requests from the clients
 	unregister chan *Client
+
+	// State changes requested by the clients
+	commands chan roomCommand
+}
+
+// Publisher pushes server events to the clients of a room from outside of
+// the websocket connections, such as from REST handlers
+type Publisher interface {
+	Publish(roomId, msgType string, payload any)
 }
 
-func NewHub() *Hub {
+func NewHub(svc *Services) *Hub {
 	return &Hub{
+		svc:        svc,
 		broadcast:  make(chan incomingMessage),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		commands:   make(chan roomCommand),
 		rooms:      make(map[string]map[*Client]bool),
+		states:     make(map[string]*RoomState),
 	}
 }
 
@@ -40,6 +70,19 @@ func (h *Hub) Run() {
 				h.rooms[client.roomId] = room
 			}
 			room[client] = true
+
+			// Tell the client who it is in the room, anonymous clients
+			// need their participant id to keep it across reconnects
+			if welcome, err := encode(TypeWelcome, "", client.joinPayload()); err == nil {
+				h.sendTo(client, welcome)
+			}
+
+			// Replay the live state so late joiners land on the current slide
+			if state := h.states[client.roomId]; state != nil {
+				h.sendTo(client, h.encodeState(state))
+			}
+			h.sendTo(client, client.backlog)
+			client.backlog = nil
 		case client := <-h.unregister:
 			room := h.rooms[client.roomId]
 			if room != nil {
@@ -49,26 +92,135 @@ func (h *Hub) Run() {
 					if len(room) == 0 {
 						// This was last client in the room, delete the room
 						delete(h.rooms, client.roomId)
+						h.dropEndedState(client.roomId)
 					}
 				}
 			}
 		case incomingMessage := <-h.broadcast:
-			room := h.rooms[incomingMessage.roomId]
-			if room != nil {
-				for client := range room {
-					select {
-					case client.send <- incomingMessage.data:
-					default:
-						close(client.send)
-						delete(room, client)
-					}
-				}
-				if len(room) == 0 {
-					// The room was emptied while broadcasting to the room
-					// Delete the room
-					delete(h.rooms, incomingMessage.roomId)
-				}
+			if incomingMessage.target != nil {
+				h.sendTo(incomingMessage.target, incomingMessage.data)
+			} else {
+				h.sendToRoom(incomingMessage.roomId, incomingMessage.data, incomingMessage.roles...)
 			}
+		case cmd := <-h.commands:
+			h.apply(cmd)
+		}
+	}
+}
+
+// Publish sends an event to every client of a room
+func (h *Hub) Publish(roomId, msgType string, payload any) {
+	data, err := encode(msgType, "", payload)
+	if err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	h.broadcast <- incomingMessage{roomId: roomId, data: data}
+}
+
+// apply runs a state change on behalf of a client, or of the hub itself when
+// the command has no client, then broadcasts the new state to the room and
+// acknowledges the sender
+func (h *Hub) apply(cmd roomCommand) {
+	state := h.states[cmd.roomId]
+
+	next, err := cmd.apply(state)
+	if err != nil {
+		if cmd.client != nil {
+			h.sendError(cmd.client, cmd.ref, err)
+		}
+		return
+	}
+	if next == nil {
+		return
+	}
+
+	h.states[cmd.roomId] = next
+	h.syncQuestion(cmd.roomId, next)
+	if cmd.silent {
+		return
+	}
+
+	h.sendToRoom(cmd.roomId, h.encodeState(next))
+	if cmd.client == nil {
+		return
+	}
+	if ack, err := encode(TypeAck, cmd.ref, nil); err == nil {
+		h.sendTo(cmd.client, ack)
+	}
+}
+
+func (h *Hub) dropEndedState(roomId string) {
+	if state := h.states[roomId]; state != nil && state.Status == StatusEnded {
+		delete(h.states, roomId)
+	}
+}
+
+func (h *Hub) encodeState(state *RoomState) []byte {
+	data, err := encode(TypeState, "", state)
+	if err != nil {
+		log.Printf("error: %+v", err)
+	}
+	return data
+}
+
+func (h *Hub) sendError(client *Client, ref string, err error) {
+	data, err := encodeError(ref, err)
+	if err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	h.sendTo(client, data)
+}
+
+// sendTo delivers a message to a single registered client
+func (h *Hub) sendTo(client *Client, data []byte) {
+	room := h.rooms[client.roomId]
+	if room == nil || data == nil {
+		return
+	}
+	if _, ok := room[client]; !ok {
+		return
+	}
+	select {
+	case client.send <- data:
+	default:
+		close(client.send)
+		delete(room, client)
+	}
+}
+
+// sendToRoom delivers a message to every client of a room, or to those
+// having one of the given roles
+func (h *Hub) sendToRoom(roomId string, data []byte, roles ...Role) {
+	room := h.rooms[roomId]
+	if room == nil || data == nil {
+		return
+	}
+	for client := range room {
+		if len(roles) > 0 && !hasRole(roles, client.role) {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+			close(client.send)
+			delete(room, client)
+		}
+	}
+	if len(room) == 0 {
+		// The room was emptied while broadcasting to the room
+		// Delete the room
+		delete(h.rooms, roomId)
+		h.dropEndedState(roomId)
+	}
+}
+
+func hasRole(roles []Role, role Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,207 @@
+package websocket
+
+import (
+	"advanced-webapp-project/model"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+)
+
+// Version of the message protocol spoken over the websocket connection.
+// Frames carrying any other version are rejected
+const protocolVersion = 1
+
+// Message types understood by the server or emitted by it
+const (
+	TypeWelcome      = "welcome"
+	TypeJoin         = "join"
+	TypeVote         = "vote"
+	TypeChangeSlide  = "change_slide"
+	TypeNextSlide    = "next_slide"
+	TypePrevSlide    = "prev_slide"
+	TypeStart        = "start"
+	TypePause        = "pause"
+	TypeEnd          = "end"
+	TypeState        = "state"
+	TypeModerate     = "moderate"
+	TypeResults      = "results"
+	TypeAnswer       = "answer"
+	TypeAsk          = "ask"
+	TypeUpvote       = "upvote"
+	TypeMarkAnswered = "mark_answered"
+	TypeQuestion     = "question"
+	TypeChat         = "chat"
+	TypeChatHistory  = "chat_history"
+	TypeLeaderboard  = "leaderboard"
+	TypeTally        = "tally"
+	TypeHeatmap      = "heatmap"
+	TypeRevealWinner = "reveal_winner"
+	TypeError        = "error"
+	TypeAck          = "ack"
+)
+
+// Error codes carried by an error message
+const (
+	ErrCodeMalformed   = "malformed"
+	ErrCodeVersion     = "unsupported_version"
+	ErrCodeUnknownType = "unknown_type"
+	ErrCodeBadPayload  = "bad_payload"
+	ErrCodeNotFound    = "not_found"
+	ErrCodeForbidden   = "forbidden"
+	ErrCodeConflict    = "conflict"
+	ErrCodeInternal    = "internal"
+)
+
+// Envelope wraps every frame exchanged between the server and the clients
+type Envelope struct {
+	Version int    `json:"v"`
+	Type    string `json:"type"`
+
+	// Ref is an optional client generated id echoed back in the ack or error
+	// answering that frame
+	Ref string `json:"ref,omitempty"`
+
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type JoinPayload struct {
+	RoomId        string `json:"room_id"`
+	UserId        string `json:"user_id,omitempty"`
+	Role          Role   `json:"role"`
+	ParticipantId string `json:"participant_id"`
+}
+
+// VotePayload is the response of a participant to a slide. Which fields are
+// expected depends on the type of the slide
+type VotePayload struct {
+	SlideId uint `json:"slide_id"`
+
+	// Multiple choice, select answer and who will win
+	OptionId uint `json:"option_id,omitempty"`
+
+	// Word cloud
+	Word string `json:"word,omitempty"`
+
+	// Open ended and type answer
+	Text string `json:"text,omitempty"`
+
+	// Scales
+	Ratings []*model.Rating `json:"ratings,omitempty"`
+
+	// Ranking, option ids from most to least preferred
+	Ranking []uint `json:"ranking,omitempty"`
+
+	// 100 points
+	Allocations []*model.Allocation `json:"allocations,omitempty"`
+
+	// 2x2 grid
+	GridPoints []*model.GridPoint `json:"grid_points,omitempty"`
+
+	// Pin on image
+	Pin *model.Pin `json:"pin,omitempty"`
+}
+
+type RevealWinnerPayload struct {
+	SlideId  uint `json:"slide_id"`
+	OptionId uint `json:"option_id"`
+}
+
+// HeatmapPayload asks for the heatmap of a pin on image slide at a chosen
+// resolution
+type HeatmapPayload struct {
+	SlideId    uint `json:"slide_id"`
+	Resolution int  `json:"resolution"`
+}
+
+type ModeratePayload struct {
+	SlideId  uint `json:"slide_id"`
+	AnswerId uint `json:"answer_id"`
+	model.AnswerModeration
+}
+
+type ChangeSlidePayload struct {
+	SlideId uint `json:"slide_id"`
+}
+
+type AskPayload struct {
+	Question string `json:"question"`
+}
+
+// LeaderboardPayload is announced when a quiz question closes, along with
+// the question and its correct answers, and once more when the presentation
+// ends
+type LeaderboardPayload struct {
+	Final       bool                      `json:"final"`
+	Slide       *model.Slide              `json:"slide,omitempty"`
+	Tally       *model.QuizTally          `json:"tally,omitempty"`
+	Leaderboard []*model.LeaderboardEntry `json:"leaderboard"`
+}
+
+type ChatPayload struct {
+	Message string `json:"message"`
+}
+
+type QuestionPayload struct {
+	QuestionId uint `json:"question_id"`
+	IsAnswered bool `json:"is_answered,omitempty"`
+}
+
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// protocolError is returned by message handlers to reject a frame with a
+// structured error sent back to its sender only
+type protocolError struct {
+	code    string
+	message string
+}
+
+func (e *protocolError) Error() string {
+	return e.code + ": " + e.message
+}
+
+func newProtocolError(code, message string) *protocolError {
+	return &protocolError{code: code, message: message}
+}
+
+// encode builds a frame of the given type around the payload
+func encode(msgType, ref string, payload any) ([]byte, error) {
+	env := Envelope{Version: protocolVersion, Type: msgType, Ref: ref}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		env.Data = data
+	}
+	return json.Marshal(env)
+}
+
+// encodeError builds an error frame answering the frame identified by ref.
+// Errors other than protocolError are logged and reported as internal errors
+// so that no server detail leaks to the client
+func encodeError(ref string, err error) ([]byte, error) {
+	var protoErr *protocolError
+	if !errors.As(err, &protoErr) {
+		log.Printf("error: %+v", err)
+		protoErr = newProtocolError(ErrCodeInternal, "internal server error")
+	}
+	return encode(TypeError, ref, ErrorPayload{Code: protoErr.code, Message: protoErr.message})
+}
+
+// decode unmarshals the payload of a frame, rejecting unknown fields so that
+// typos in client payloads surface as errors instead of zero values
+func decode(env *Envelope, payload any) error {
+	if len(env.Data) == 0 {
+		return newProtocolError(ErrCodeBadPayload, "missing data")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(env.Data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return newProtocolError(ErrCodeBadPayload, err.Error())
+	}
+	return nil
+}
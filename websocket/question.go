@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+)
+
+// handleAsk posts a question of a participant to the Q&A of the presentation
+// of the room
+func handleAsk(c *Client, env *Envelope) error {
+	var payload AskPayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	question := &model.Question{
+		UserId:         utils.Str2Uint(c.userId),
+		PresentationId: utils.Str2Uint(c.roomId),
+		ParticipantId:  c.participantId,
+		Question:       payload.Question,
+		Username:       c.username,
+	}
+	if err := c.svc.Question.AskQuestion(question); err != nil {
+		return translateError(err)
+	}
+
+	c.broadcast(TypeQuestion, question)
+	c.reply(TypeAck, env.Ref, question)
+	return nil
+}
+
+func handleUpvote(c *Client, env *Envelope) error {
+	var payload QuestionPayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	question, err := c.svc.Question.UpvoteQuestion(c.roomId, utils.Uint2Str(payload.QuestionId), c.userId, c.participantId)
+	if err != nil {
+		return translateError(err)
+	}
+
+	c.broadcast(TypeQuestion, question)
+	c.reply(TypeAck, env.Ref, nil)
+	return nil
+}
+
+func handleMarkAnswered(c *Client, env *Envelope) error {
+	var payload QuestionPayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	question, err := c.svc.Question.MarkAnswered(c.roomId, utils.Uint2Str(payload.QuestionId), payload.IsAnswered)
+	if err != nil {
+		return translateError(err)
+	}
+
+	c.broadcast(TypeQuestion, question)
+	c.reply(TypeAck, env.Ref, nil)
+	return nil
+}
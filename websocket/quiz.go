@@ -0,0 +1,170 @@
+package websocket
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/service"
+	"advanced-webapp-project/utils"
+	"log"
+	"time"
+)
+
+// QuizQuestion is the timed question of a quiz slide. The hub opens it when
+// the room moves to the slide and closes it when its time is up or the room
+// moves away, announcing the leaderboard
+type QuizQuestion struct {
+	SlideId   uint      `json:"slide_id"`
+	TimeLimit uint      `json:"time_limit"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Closed    bool      `json:"closed"`
+
+	timer     *time.Timer
+	announced bool
+}
+
+func (q *QuizQuestion) close() {
+	q.timer.Stop()
+	q.Closed = true
+}
+
+// syncQuestion opens and closes the quiz question of a room after each state
+// change, and announces the leaderboards. Pausing the presentation does not
+// stop the clock of the open question
+func (h *Hub) syncQuestion(roomId string, state *RoomState) {
+	q := state.Question
+	if q != nil && !q.Closed && (state.Status == StatusEnded || state.Slide.Id != q.SlideId) {
+		q.close()
+	}
+
+	if state.Status == StatusEnded {
+		// The final leaderboard stands for the one of the last question
+		if !state.finalized && hasQuiz(state.slides) {
+			state.finalized = true
+			if q != nil {
+				q.announced = true
+			}
+			go h.announceLeaderboard(roomId, state.SessionId, 0, true)
+		}
+		return
+	}
+
+	if q != nil && q.Closed && !q.announced {
+		q.announced = true
+		go h.announceLeaderboard(roomId, state.SessionId, q.SlideId, false)
+	}
+
+	if state.Status == StatusStarted && service.IsQuiz(state.Slide.Type) && (q == nil || q.SlideId != state.Slide.Id) {
+		state.Question = h.openQuestion(roomId, state.Slide)
+	}
+}
+
+// openQuestion starts the clock of a quiz question, closing it through a
+// command of the hub once the time is up
+func (h *Hub) openQuestion(roomId string, slide *model.Slide) *QuizQuestion {
+	limit := service.QuizTimeLimit(slide.Content)
+	now := time.Now()
+	q := &QuizQuestion{
+		SlideId:   slide.Id,
+		TimeLimit: uint(limit / time.Second),
+		StartedAt: now,
+		EndsAt:    now.Add(limit),
+	}
+
+	q.timer = time.AfterFunc(limit, func() {
+		h.commands <- roomCommand{roomId: roomId, apply: func(state *RoomState) (*RoomState, error) {
+			if state == nil || state.Question != q || q.Closed {
+				return nil, nil
+			}
+			q.Closed = true
+			return state, nil
+		}}
+	})
+	return q
+}
+
+// announceLeaderboard sends the leaderboard of the session of a room, with
+// the question that just closed unless it is the final one. It runs outside
+// of the hub goroutine as it queries the database
+func (h *Hub) announceLeaderboard(roomId string, sessionId, slideId uint, final bool) {
+	payload := LeaderboardPayload{Final: final}
+	quiz := h.svc.Quiz.InSession(sessionId)
+
+	var err error
+	if payload.Leaderboard, err = quiz.GetLeaderboard(roomId); err != nil {
+		log.Printf("error: %+v", err)
+		return
+	}
+	if !final {
+		if payload.Slide, err = h.svc.Slide.InSession(sessionId).GetSlideById(utils.Uint2Str(slideId)); err != nil {
+			log.Printf("error: %+v", err)
+			return
+		}
+		if payload.Tally, err = quiz.GetTally(payload.Slide); err != nil {
+			log.Printf("error: %+v", err)
+			return
+		}
+	}
+
+	h.Publish(roomId, TypeLeaderboard, payload)
+}
+
+// answerQuestion times the answer of a participant against the open question
+// of the room, then scores and records it. The participant is told whether
+// the answer is correct and the presenters get the updated tally
+func (c *Client) answerQuestion(ref string, slide *model.Slide, payload *VotePayload) {
+	c.hub.commands <- roomCommand{roomId: c.roomId, client: c, ref: ref, silent: true, apply: func(state *RoomState) (*RoomState, error) {
+		if state == nil || state.Status != StatusStarted {
+			return nil, newProtocolError(ErrCodeForbidden, "presentation is not running")
+		}
+		q := state.Question
+		if q == nil || q.SlideId != slide.Id || q.Closed || time.Now().After(q.EndsAt) {
+			return nil, newProtocolError(ErrCodeForbidden, "question is not open")
+		}
+
+		elapsed := time.Since(q.StartedAt)
+		quiz := c.svc.Quiz.InSession(state.SessionId)
+		go func() {
+			response, err := quiz.SubmitQuizAnswer(slide, c.participantId, c.username, payload.OptionId, payload.Text, elapsed)
+			if err != nil {
+				c.replyError(ref, translateError(err))
+				return
+			}
+			c.reply(TypeAck, ref, response)
+
+			tally, err := quiz.GetTally(slide)
+			if err != nil {
+				log.Printf("error: %+v", err)
+				return
+			}
+			c.broadcastTo(presenterOnly, TypeTally, tally)
+		}()
+		return nil, nil
+	}}
+}
+
+// publicQuiz is a quiz slide without its correct answers. The accepted
+// answers of type answer questions are all correct, so they are left out
+func publicQuiz(slide *model.Slide) *model.Slide {
+	content := *slide.Content
+	content.Options = nil
+	if slide.Type == model.SlideTypeSelectAnswer {
+		for _, option := range slide.Content.Options {
+			public := *option
+			public.IsCorrect = false
+			content.Options = append(content.Options, &public)
+		}
+	}
+
+	public := *slide
+	public.Content = &content
+	return &public
+}
+
+func hasQuiz(slides []*model.Slide) bool {
+	for _, slide := range slides {
+		if service.IsQuiz(slide.Type) {
+			return true
+		}
+	}
+	return false
+}
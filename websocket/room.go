@@ -0,0 +1,272 @@
+package websocket
+
+import (
+	"advanced-webapp-project/model"
+	"advanced-webapp-project/utils"
+)
+
+// Presentation statuses of a room
+const (
+	StatusStarted = "started"
+	StatusPaused  = "paused"
+	StatusEnded   = "ended"
+)
+
+// RoomState is the live presentation state of a room. It is owned by the hub
+// goroutine and must only be read or modified from there
+type RoomState struct {
+	PresentationId uint         `json:"presentation_id"`
+	SlideIndex     int          `json:"slide_index"`
+	SlideCount     int          `json:"slide_count"`
+	Status         string       `json:"status"`
+	Slide          *model.Slide `json:"slide,omitempty"`
+
+	// Session the responses of the room are recorded in
+	SessionId uint `json:"session_id"`
+
+	// Quiz question of the current or last visited quiz slide
+	Question *QuizQuestion `json:"question,omitempty"`
+
+	slides []*model.Slide
+
+	// Whether the final leaderboard was announced
+	finalized bool
+}
+
+// roomCommand asks the hub to apply a change to the state of a room. apply
+// runs on the hub goroutine and returns the new state, or a protocolError to
+// reject the change
+type roomCommand struct {
+	roomId string
+	client *Client
+	ref    string
+
+	// Whether the new state is kept without being broadcast to the room
+	silent bool
+
+	apply func(state *RoomState) (*RoomState, error)
+}
+
+// RoomUpdater applies changes made outside of the websocket connections, such
+// as from REST handlers, to the live state of the rooms
+type RoomUpdater interface {
+	ReorderSlides(roomId string, slideIds []uint)
+}
+
+// ReorderSlides puts the slides of a live room in the given order, keeping
+// the room on its current slide
+func (h *Hub) ReorderSlides(roomId string, slideIds []uint) {
+	h.commands <- roomCommand{roomId: roomId, apply: func(state *RoomState) (*RoomState, error) {
+		if state == nil {
+			return nil, nil
+		}
+		state.reorder(slideIds)
+		return state, nil
+	}}
+}
+
+func (s *RoomState) goTo(index int) error {
+	if s.Status == StatusEnded {
+		return newProtocolError(ErrCodeForbidden, "presentation has ended")
+	}
+	if index < 0 || index >= len(s.slides) {
+		return newProtocolError(ErrCodeNotFound, "no slide at this position")
+	}
+	s.SlideIndex = index
+	s.Slide = s.slides[index]
+	return nil
+}
+
+func (s *RoomState) indexOf(slideId uint) int {
+	for i, slide := range s.slides {
+		if slide.Id == slideId {
+			return i
+		}
+	}
+	return -1
+}
+
+// reorder sorts the slides of the room by the given ids. Slides left out of
+// them keep their relative order after the others
+func (s *RoomState) reorder(slideIds []uint) {
+	byId := make(map[uint]*model.Slide, len(s.slides))
+	for _, slide := range s.slides {
+		byId[slide.Id] = slide
+	}
+
+	slides := make([]*model.Slide, 0, len(s.slides))
+	for _, id := range slideIds {
+		if slide, ok := byId[id]; ok {
+			slides = append(slides, slide)
+			delete(byId, id)
+		}
+	}
+	for _, slide := range s.slides {
+		if _, ok := byId[slide.Id]; ok {
+			slides = append(slides, slide)
+		}
+	}
+
+	s.slides = slides
+	s.SlideIndex = s.indexOf(s.Slide.Id)
+}
+
+func requireState(state *RoomState) error {
+	if state == nil {
+		return newProtocolError(ErrCodeForbidden, "presentation has not started")
+	}
+	return nil
+}
+
+// handleStart starts the presentation in a new session, or resumes the
+// session of a paused presentation
+func handleStart(c *Client, env *Envelope) error {
+	presId := utils.Str2Uint(c.roomId)
+	sessionId := c.roomSession()
+	if sessionId == 0 {
+		session, err := c.svc.Session.StartSession(c.roomId, c.groupId)
+		if err != nil {
+			return err
+		}
+		sessionId = session.Id
+	}
+
+	slides, err := c.svc.Slide.InSession(sessionId).GetAllSlides(c.roomId)
+	if err != nil {
+		return err
+	}
+	for i, slide := range slides {
+		slides[i] = publicSlide(slide)
+	}
+	if len(slides) == 0 {
+		return newProtocolError(ErrCodeNotFound, "presentation has no slides")
+	}
+
+	c.command(env.Ref, func(state *RoomState) (*RoomState, error) {
+		// Resuming a paused presentation keeps the current slide
+		if state != nil && state.PresentationId == presId && state.Status == StatusPaused {
+			state.Status = StatusStarted
+			return state, nil
+		}
+
+		next := &RoomState{
+			PresentationId: presId,
+			SlideCount:     len(slides),
+			Status:         StatusStarted,
+			SessionId:      sessionId,
+			slides:         slides,
+		}
+		return next, next.goTo(0)
+	})
+	return nil
+}
+
+func handlePause(c *Client, env *Envelope) error {
+	c.command(env.Ref, func(state *RoomState) (*RoomState, error) {
+		if err := requireState(state); err != nil {
+			return nil, err
+		}
+		if state.Status != StatusStarted {
+			return nil, newProtocolError(ErrCodeForbidden, "presentation is not running")
+		}
+		state.Status = StatusPaused
+		return state, nil
+	})
+	return nil
+}
+
+// handleEnd ends the presentation along with its session, keeping the
+// responses as the results of that session. Rooms not running have no
+// session to end
+func handleEnd(c *Client, env *Envelope) error {
+	if c.roomSession() != 0 {
+		if err := c.svc.Session.EndSession(c.roomId); err != nil {
+			return err
+		}
+	}
+
+	c.command(env.Ref, func(state *RoomState) (*RoomState, error) {
+		if err := requireState(state); err != nil {
+			return nil, err
+		}
+		state.Status = StatusEnded
+		return state, nil
+	})
+	return nil
+}
+
+func handleChangeSlide(c *Client, env *Envelope) error {
+	var payload ChangeSlidePayload
+	if err := decode(env, &payload); err != nil {
+		return err
+	}
+
+	c.command(env.Ref, func(state *RoomState) (*RoomState, error) {
+		if err := requireState(state); err != nil {
+			return nil, err
+		}
+		index := state.indexOf(payload.SlideId)
+		if index < 0 {
+			return nil, newProtocolError(ErrCodeNotFound, "slide is not part of the presentation")
+		}
+		return state, state.goTo(index)
+	})
+	return nil
+}
+
+func handleNextSlide(c *Client, env *Envelope) error {
+	c.command(env.Ref, func(state *RoomState) (*RoomState, error) {
+		if err := requireState(state); err != nil {
+			return nil, err
+		}
+		return state, state.goTo(state.SlideIndex + 1)
+	})
+	return nil
+}
+
+func handlePrevSlide(c *Client, env *Envelope) error {
+	c.command(env.Ref, func(state *RoomState) (*RoomState, error) {
+		if err := requireState(state); err != nil {
+			return nil, err
+		}
+		return state, state.goTo(state.SlideIndex - 1)
+	})
+	return nil
+}
+
+// refreshSlide replaces the cached copy of a slide in the room state, so that
+// late joiners are replayed up to date results
+func (c *Client) refreshSlide(slide *model.Slide) {
+	c.hub.commands <- roomCommand{roomId: c.roomId, client: c, silent: true, apply: func(state *RoomState) (*RoomState, error) {
+		if state == nil {
+			return nil, nil
+		}
+		if index := state.indexOf(slide.Id); index >= 0 {
+			state.slides[index] = slide
+			if index == state.SlideIndex {
+				state.Slide = slide
+			}
+		}
+		return state, nil
+	}}
+}
+
+// roomSession is the session of the presentation running in the room, or
+// zero when the presentation is not running
+func (c *Client) roomSession() uint {
+	session := make(chan uint, 1)
+	c.hub.commands <- roomCommand{roomId: c.roomId, client: c, silent: true, apply: func(state *RoomState) (*RoomState, error) {
+		if state == nil || state.Status == StatusEnded {
+			session <- 0
+		} else {
+			session <- state.SessionId
+		}
+		return nil, nil
+	}}
+	return <-session
+}
+
+// command hands a state change over to the hub goroutine
+func (c *Client) command(ref string, apply func(state *RoomState) (*RoomState, error)) {
+	c.hub.commands <- roomCommand{roomId: c.roomId, client: c, ref: ref, apply: apply}
+}